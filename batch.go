@@ -0,0 +1,92 @@
+package sqlBuilder
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// UpdateBatch 按 keyColumn 批量更新多行不同的值，每批生成一条
+// UPDATE ... SET col = CASE key WHEN ? THEN ? ... ELSE col END WHERE key IN (...) 语句，
+// 行中缺少的字段保持原值，每批的占位符数量不超过 MaxPlaceholders 限制。
+// Postgres 的 VALUES 参数会被推断为 text 类型，因此同样使用 CASE 形式
+func (b *Builder) UpdateBatch(keyColumn string, rows []map[string]interface{}) []Statement {
+	defer b.cleanLastSql()
+	b.initialize()
+
+	columns := make([]string, 0)
+	for _, row := range rows {
+		for column := range row {
+			if column != keyColumn && !slices.Contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+	}
+	slices.Sort(columns)
+
+	if len(rows) == 0 || len(columns) == 0 {
+		return nil
+	}
+
+	size := len(rows)
+	if limit := b.placeholderLimit(); limit > 0 {
+		// 每行占用 IN 中的一个占位符和每个字段 WHEN ? THEN ? 的两个占位符，另外为更新时间和版本号预留
+		perRow := 1 + 2*len(columns)
+		if n := (limit - len(b.params["where"]) - 2) / perRow; n > 0 && n < size {
+			size = n
+		}
+	}
+
+	key := b.escapeId(keyColumn)
+	statements := make([]Statement, 0, (len(rows)+size-1)/size)
+	for i := 0; i < len(rows); i += size {
+		chunk := rows[i:min(i+size, len(rows))]
+
+		keys := make([]interface{}, 0, len(chunk))
+		for _, row := range chunk {
+			if k, ok := row[keyColumn]; ok {
+				keys = append(keys, k)
+			}
+		}
+
+		data := make(map[string]interface{}, len(columns))
+		for _, column := range columns {
+			var (
+				when   strings.Builder
+				params []interface{}
+			)
+			for _, row := range chunk {
+				k, ok := row[keyColumn]
+				v, ok1 := row[column]
+				if !ok || !ok1 {
+					continue
+				}
+
+				value, valueParams := assignValue(v)
+				when.WriteString(" WHEN ? THEN " + value)
+				params = append(params, k)
+				params = append(params, valueParams...)
+			}
+
+			if when.Len() > 0 {
+				escaped := b.escapeId(column)
+				data[column] = Expr(fmt.Sprintf("CASE %s%s ELSE %s END", key, when.String(), escaped), params...)
+			}
+		}
+
+		if len(data) == 0 {
+			continue
+		}
+
+		bw := b.Clone()
+		bw.groupWhere()
+		sql, params := bw.Where(keyColumn, "IN", keys).Update(data)
+		if err := bw.Err(); err != nil {
+			b.setErr(err)
+			return nil
+		}
+		statements = append(statements, Statement{Sql: sql, Params: params})
+	}
+
+	return statements
+}
@@ -0,0 +1,34 @@
+package sqlBuilder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_UpdateBatch(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "张三"},
+		{"id": 2, "name": "李四"},
+		{"id": 3},
+	}
+
+	statements := NewBuilder("user").Where("tenant_id", 7).UpdateBatch("id", rows)
+	if reflect.DeepEqual(statements, []Statement{{
+		Sql:    "UPDATE `user` SET `name`=CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `name` END WHERE (`tenant_id` = ?) AND `id` IN (?,?,?)",
+		Params: []interface{}{1, "张三", 2, "李四", 7, 1, 2, 3},
+	}}) {
+		t.Log(statements)
+	} else {
+		t.Error(statements)
+	}
+
+	statements = NewBuilder("user").MaxPlaceholders(8).UpdateBatch("id", rows)
+	if reflect.DeepEqual(statements, []Statement{{
+		Sql:    "UPDATE `user` SET `name`=CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `name` END WHERE `id` IN (?,?)",
+		Params: []interface{}{1, "张三", 2, "李四", 1, 2},
+	}}) {
+		t.Log(statements)
+	} else {
+		t.Error(statements)
+	}
+}
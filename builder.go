@@ -3,19 +3,52 @@ package sqlBuilder
 import (
 	"fmt"
 	"maps"
+	"time"
 )
 
 type Raw string
 
+// RawExpr 带绑定参数的原生表达式
+type RawExpr struct {
+	Sql    string
+	Params []interface{}
+}
+
+// Expr 创建带绑定参数的原生表达式
+func Expr(sql string, params ...interface{}) RawExpr {
+	return RawExpr{Sql: sql, Params: params}
+}
+
+// NullValue 作为条件值时生成 IS NULL / IS NOT NULL
+type NullValue string
+
+const (
+	IsNull    NullValue = "NULL"
+	IsNotNull NullValue = "NOT NULL"
+)
+
 type methods struct {
-	field        []interface{}
-	where        []string
-	order        []string
-	limit        string
-	group        []string
-	having       []string
-	join         []string
-	duplicateKey map[string]interface{}
+	with              []string
+	field             []interface{}
+	where             []string
+	order             []string
+	limit             []int64 // [length] 或 [offset, length]
+	group             []string
+	having            []string
+	join              []string
+	duplicateKey      map[string]interface{}
+	duplicateKeyAlias string
+	withoutScopes     []string
+	trashed           trashedMode
+	groupedWhere      int // 已整体加括号的条件数量
+	version           interface{}
+	err               error
+}
+
+// Statement 一条SQL语句及其绑定参数
+type Statement struct {
+	Sql    string
+	Params []interface{}
 }
 
 type Builder struct {
@@ -24,6 +57,15 @@ type Builder struct {
 	TableAlias           string
 	tmpTableClosureCount uint8
 	params               map[string][]interface{}
+	dialect              *Dialect
+	strict               bool
+	maxPlaceholders      int
+	inChunkSize          int
+	clock                func() time.Time
+	executor             Executor
+	hooks                []Hook
+	lastEvent            *QueryEvent
+	lastErr              error
 
 	// 链式操作方法列表
 	methods methods
@@ -35,6 +77,45 @@ func NewBuilder(tableName string) *Builder {
 	return obj
 }
 
+// SetDialect 指定数据库方言，默认 MySQL
+func (b *Builder) SetDialect(dialect *Dialect) *Builder {
+	b.dialect = dialect
+	return b
+}
+
+func (b *Builder) GetDialect() *Dialect {
+	if b.dialect == nil {
+		return MySQL
+	}
+	return b.dialect
+}
+
+// Strict 严格模式：标识符只允许字母、数字、下划线和$，不符合时不生成SQL，错误通过 Err 获取
+func (b *Builder) Strict(strict bool) *Builder {
+	b.strict = strict
+	return b
+}
+
+// MaxPlaceholders 单条语句允许的最大绑定参数数量，0 表示使用方言默认值，负数表示不限制
+func (b *Builder) MaxPlaceholders(n int) *Builder {
+	b.maxPlaceholders = n
+	return b
+}
+
+// InChunkSize IN 列表超过 n 个值时拆分为多组 IN 条件，IN 以 OR 连接，NOT IN 以 AND 连接
+func (b *Builder) InChunkSize(n int) *Builder {
+	b.inChunkSize = n
+	return b
+}
+
+// Err 返回当前链式操作中产生的错误，构造SQL后返回最近一次构造产生的错误
+func (b *Builder) Err() error {
+	if b.methods.err != nil {
+		return b.methods.err
+	}
+	return b.lastErr
+}
+
 func (b *Builder) GetField() []interface{} {
 	return b.methods.field
 }
@@ -51,10 +132,10 @@ func (b *Builder) GetTable() string {
 	if b.tmpTable != "" {
 		table := b.tmpTable
 		if b.tmpTableClosureCount == 0 {
-			table = fmt.Sprintf("`%s`", table)
+			table = b.quoteName(table)
 
 			if b.TableAlias != "" {
-				table = fmt.Sprintf("%s as `%s`", table, b.TableAlias)
+				table = fmt.Sprintf("%s as %s", table, b.quote(b.TableAlias))
 			}
 		}
 		return table
@@ -63,7 +144,7 @@ func (b *Builder) GetTable() string {
 			return ""
 		}
 
-		return fmt.Sprintf("`%s`", b.TableName)
+		return b.quoteName(b.TableName)
 	}
 }
 
@@ -72,7 +153,7 @@ func (b *Builder) GetOrder() []string {
 }
 
 func (b *Builder) GetLimit() string {
-	return b.methods.limit
+	return b.limitSql()
 }
 
 func (b *Builder) GetGroup() []string {
@@ -91,8 +172,16 @@ func (b *Builder) Clone() *Builder {
 	obj := &Builder{
 		TableName:            b.TableName,
 		tmpTable:             b.tmpTable,
+		TableAlias:           b.TableAlias,
 		tmpTableClosureCount: b.tmpTableClosureCount,
 		params:               maps.Clone(b.params),
+		dialect:              b.dialect,
+		strict:               b.strict,
+		maxPlaceholders:      b.maxPlaceholders,
+		inChunkSize:          b.inChunkSize,
+		clock:                b.clock,
+		executor:             b.executor,
+		hooks:                b.hooks,
 		methods:              b.methods,
 	}
 
@@ -0,0 +1,278 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// TxBeginner 可以开启事务的数据库连接，*sql.DB 和 *sql.Conn 实现了该接口
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// BulkProgress 批量插入的进度
+type BulkProgress struct {
+	Batches      int
+	Rows         int
+	RowsAffected int64
+}
+
+// BulkInserter 流式批量插入，按行数、字节数和占位符数量拆分为多条插入语句依次执行
+type BulkInserter struct {
+	builder    *Builder
+	batchRows  int
+	batchBytes int
+	txPerBatch bool
+	progress   func(BulkProgress)
+}
+
+// NewBulkInserter 以 b 为模板创建批量插入，b 的表名、方言、DuplicateKey 和数据库连接会应用到每一批
+func NewBulkInserter(b *Builder) *BulkInserter {
+	return &BulkInserter{builder: b, batchRows: 1000}
+}
+
+// BatchRows 每批最多插入的行数，默认 1000
+func (bi *BulkInserter) BatchRows(n int) *BulkInserter {
+	bi.batchRows = n
+	return bi
+}
+
+// BatchBytes 每批语句的最大字节数（SQL 与参数的估算大小），0 表示不限制
+func (bi *BulkInserter) BatchBytes(n int) *BulkInserter {
+	bi.batchBytes = n
+	return bi
+}
+
+// TxPerBatch 每批在单独的事务中执行，需要数据库连接实现 TxBeginner
+func (bi *BulkInserter) TxPerBatch(txPerBatch bool) *BulkInserter {
+	bi.txPerBatch = txPerBatch
+	return bi
+}
+
+// OnProgress 每批执行完成后回调累计进度
+func (bi *BulkInserter) OnProgress(fn func(BulkProgress)) *BulkInserter {
+	bi.progress = fn
+	return bi
+}
+
+type bulkBatch struct {
+	Statement
+	event *QueryEvent
+	rows  int
+}
+
+// Statements 将行数据拆分为多条插入语句，字段按名称排序，字段不同的行会拆分到不同的批次
+func (bi *BulkInserter) Statements(rows iter.Seq[map[string]interface{}]) iter.Seq2[Statement, error] {
+	return func(yield func(Statement, error) bool) {
+		for batch, err := range bi.batches(rows) {
+			if !yield(batch.Statement, err) {
+				return
+			}
+		}
+	}
+}
+
+func (bi *BulkInserter) batches(rows iter.Seq[map[string]interface{}]) iter.Seq2[bulkBatch, error] {
+	return func(yield func(bulkBatch, error) bool) {
+		var (
+			batch   []interface{}
+			columns []string
+			size    int
+		)
+
+		flush := func() bool {
+			if len(batch) == 0 {
+				return true
+			}
+
+			bw := bi.builder.Clone()
+			query, params := bw.build("INSERT", func() (string, []interface{}) {
+				return bw.insertRowsSql("INSERT", batch, []string{})
+			})
+			n := len(batch)
+			batch, size = batch[:0], 0
+
+			return yield(bulkBatch{Statement: Statement{Sql: query, Params: params}, event: bw.execEvent(query, params), rows: n}, bw.Err())
+		}
+
+		limit := bi.builder.placeholderLimit() - len(bi.builder.methods.duplicateKey) - 2
+		for row := range rows {
+			rowColumns := slices.Sorted(maps.Keys(row))
+			rowSize := rowBytes(row)
+
+			full := len(batch) > 0 && (!slices.Equal(columns, rowColumns) ||
+				(bi.batchRows > 0 && len(batch) >= bi.batchRows) ||
+				(bi.batchBytes > 0 && size+rowSize > bi.batchBytes) ||
+				(limit > 0 && (len(batch)+1)*(len(row)+2) > limit))
+			if full && !flush() {
+				return
+			}
+
+			columns = rowColumns
+			batch = append(batch, row)
+			size += rowSize
+		}
+
+		flush()
+	}
+}
+
+// rowBytes 估算一行数据在语句中占用的字节数
+func rowBytes(row map[string]interface{}) int {
+	size := 3
+	for _, v := range row {
+		size += 2
+		switch v := v.(type) {
+		case string:
+			size += len(v)
+		case []byte:
+			size += len(v)
+		default:
+			size += 8
+		}
+	}
+	return size
+}
+
+// Run 批量插入 rows 中的数据
+func (bi *BulkInserter) Run(ctx context.Context, rows iter.Seq[map[string]interface{}]) (BulkProgress, error) {
+	var progress BulkProgress
+
+	executor := bi.builder.executor
+	if executor == nil {
+		return progress, ErrNoExecutor
+	}
+
+	for batch, err := range bi.batches(rows) {
+		if err != nil {
+			return progress, err
+		}
+
+		result, err := runExec(ctx, bi.builder.getHooks(), batch.event, func(ctx context.Context, query string, params []interface{}) (sql.Result, error) {
+			return bi.exec(ctx, executor, Statement{Sql: query, Params: params})
+		})
+		if err != nil {
+			return progress, err
+		}
+
+		affected, _ := result.RowsAffected()
+		progress.Batches++
+		progress.Rows += batch.rows
+		progress.RowsAffected += affected
+
+		if bi.progress != nil {
+			bi.progress(progress)
+		}
+	}
+
+	return progress, nil
+}
+
+func (bi *BulkInserter) exec(ctx context.Context, executor Executor, statement Statement) (sql.Result, error) {
+	if !bi.txPerBatch {
+		return executor.ExecContext(ctx, statement.Sql, statement.Params...)
+	}
+
+	db, ok := executor.(TxBeginner)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrTxUnsupported, executor)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx, statement.Sql, statement.Params...)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return result, tx.Commit()
+}
+
+// RunChan 批量插入从 channel 中读取的数据，channel 关闭后结束
+func (bi *BulkInserter) RunChan(ctx context.Context, rows <-chan map[string]interface{}) (BulkProgress, error) {
+	progress, err := bi.Run(ctx, func(yield func(map[string]interface{}) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case row, ok := <-rows:
+				if !ok || !yield(row) {
+					return
+				}
+			}
+		}
+	})
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	return progress, err
+}
+
+// RunStructs 批量插入结构体切片，字段名取 db 标签，标签为 "-" 的字段和未导出的字段会被忽略
+func (bi *BulkInserter) RunStructs(ctx context.Context, rows interface{}) (BulkProgress, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return BulkProgress{}, fmt.Errorf("%w: %T", ErrInvalidRows, rows)
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if _, ok := structValue(v.Index(i)); !ok {
+			return BulkProgress{}, fmt.Errorf("%w: %s at index %d", ErrInvalidRows, v.Index(i).Type(), i)
+		}
+	}
+
+	return bi.Run(ctx, func(yield func(map[string]interface{}) bool) {
+		for i := 0; i < v.Len(); i++ {
+			if !yield(structToMap(v.Index(i))) {
+				return
+			}
+		}
+	})
+}
+
+// structValue 解引用指针和接口，返回结构体的值，为 nil 或不是结构体时返回 false
+func structValue(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, v.Kind() == reflect.Struct
+}
+
+func structToMap(v reflect.Value) map[string]interface{} {
+	v, _ = structValue(v)
+
+	t := v.Type()
+	row := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		row[name] = v.Field(i).Interface()
+	}
+
+	return row
+}
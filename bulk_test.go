@@ -0,0 +1,100 @@
+package sqlBuilder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestBulkInserter_Statements(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "a"},
+		{"id": 2, "name": "b"},
+		{"id": 3, "name": "c"},
+		{"id": 4},
+	}
+
+	statements := make([]Statement, 0)
+	for statement, err := range NewBulkInserter(NewBuilder("user")).BatchRows(2).Statements(slices.Values(rows)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		statements = append(statements, statement)
+	}
+
+	if reflect.DeepEqual(statements, []Statement{
+		{Sql: "INSERT INTO `user` (`id`,`name`) VALUES(?,?),(?,?)", Params: []interface{}{1, "a", 2, "b"}},
+		{Sql: "INSERT INTO `user` (`id`,`name`) VALUES(?,?)", Params: []interface{}{3, "c"}},
+		{Sql: "INSERT INTO `user` (`id`) VALUES(?)", Params: []interface{}{4}},
+	}) {
+		t.Log(statements)
+	} else {
+		t.Error(statements)
+	}
+
+	statements = statements[:0]
+	for statement := range NewBulkInserter(NewBuilder("user")).BatchBytes(40).Statements(slices.Values(rows[:3])) {
+		statements = append(statements, statement)
+	}
+	if len(statements) == 2 {
+		t.Log(statements)
+	} else {
+		t.Error(statements)
+	}
+}
+
+func TestBulkInserter_Run(t *testing.T) {
+	type user struct {
+		Id      int    `db:"id"`
+		Name    string `db:"name"`
+		Ignored string `db:"-"`
+		secret  string
+	}
+
+	db, fake := openFakeDB(t)
+	progress := make([]BulkProgress, 0)
+
+	result, err := NewBulkInserter(NewBuilder("user").SetExecutor(db)).
+		BatchRows(2).
+		TxPerBatch(true).
+		OnProgress(func(p BulkProgress) {
+			progress = append(progress, p)
+		}).
+		RunStructs(context.Background(), []user{{Id: 1, Name: "a"}, {Id: 2, Name: "b"}, {Id: 3, Name: "c", secret: "x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := fake.queries()
+	if result == (BulkProgress{Batches: 2, Rows: 3, RowsAffected: 2}) && len(progress) == 2 &&
+		reflect.DeepEqual(queries, []string{
+			"BEGIN", "INSERT INTO `user` (`id`,`name`) VALUES(?,?),(?,?)", "COMMIT",
+			"BEGIN", "INSERT INTO `user` (`id`,`name`) VALUES(?,?)", "COMMIT",
+		}) {
+		t.Log(result, queries)
+	} else {
+		t.Error(result, progress, queries)
+	}
+
+	rows := make(chan map[string]interface{}, 3)
+	rows <- map[string]interface{}{"id": 1}
+	rows <- map[string]interface{}{"id": 2}
+	close(rows)
+	result, err = NewBulkInserter(NewBuilder("user").SetExecutor(db)).RunChan(context.Background(), rows)
+	if err == nil && result.Rows == 2 && result.Batches == 1 {
+		t.Log(result)
+	} else {
+		t.Error(result, err)
+	}
+
+	for _, rows := range []interface{}{[]int{1, 2}, []*user{{Id: 1}, nil}, []interface{}{user{Id: 1}, "a"}, user{}} {
+		result, err = NewBulkInserter(NewBuilder("user").SetExecutor(db)).RunStructs(context.Background(), rows)
+		if errors.Is(err, ErrInvalidRows) && result == (BulkProgress{}) {
+			t.Log(err)
+		} else {
+			t.Error(result, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package sqlBuilder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect 数据库方言
+type Dialect struct {
+	Name       string
+	quoteOpen  string
+	quoteClose string
+	// bindVar 驱动使用的位置参数前缀，例如 Postgres 的 $1、SQLServer 的 @p1，为空时使用 ?
+	bindVar string
+	// maxParams 单条语句允许的最大绑定参数数量
+	maxParams int
+	// now 当前时间函数
+	now string
+	// retryCodes 可以通过重新执行事务解决的错误码
+	retryCodes []string
+	// constraintCodes 违反约束的错误码
+	constraintCodes []string
+	// schemaCodes 表结构变更导致预处理语句失效的错误码
+	schemaCodes []string
+}
+
+var (
+	MySQL     = &Dialect{Name: "mysql", quoteOpen: "`", quoteClose: "`", maxParams: 65535, now: "NOW()", retryCodes: []string{"1213", "40001"}, constraintCodes: []string{"1062", "1451", "1452", "1048", "23000"}, schemaCodes: []string{"1615"}}
+	Postgres  = &Dialect{Name: "postgres", quoteOpen: `"`, quoteClose: `"`, bindVar: "$", maxParams: 65535, now: "NOW()", retryCodes: []string{"40001", "40P01"}, constraintCodes: []string{"23505", "23503", "23502", "23514", "23P01"}, schemaCodes: []string{"0A000", "26000"}}
+	SQLite    = &Dialect{Name: "sqlite", quoteOpen: `"`, quoteClose: `"`, maxParams: 32766, now: "CURRENT_TIMESTAMP", retryCodes: []string{"5", "6"}, constraintCodes: []string{"19"}, schemaCodes: []string{"17"}}
+	SQLServer = &Dialect{Name: "sqlserver", quoteOpen: "[", quoteClose: "]", bindVar: "@p", maxParams: 2100, now: "SYSDATETIME()", retryCodes: []string{"1205"}, constraintCodes: []string{"2627", "2601", "547", "515"}, schemaCodes: []string{"8179"}}
+)
+
+// Quote 引用标识符，标识符内的结束引号会被双写转义
+func (d *Dialect) Quote(id string) string {
+	return d.quoteOpen + strings.ReplaceAll(id, d.quoteClose, d.quoteClose+d.quoteClose) + d.quoteClose
+}
+
+// Rebind 将语句中的 ? 占位符替换为驱动使用的位置参数，例如 Postgres 的 $1、SQLServer 的 @p1，
+// 字符串、带引号的标识符和注释中的 ? 不替换。执行时自动调用，钩子中看到的仍然是 ? 占位符
+func (d *Dialect) Rebind(query string) string {
+	if d == nil || d.bindVar == "" || strings.IndexByte(query, '?') < 0 {
+		return query
+	}
+
+	var s strings.Builder
+	n := 0
+	for i := 0; i < len(query); {
+		c := query[i]
+		end := i + 1
+		switch {
+		case identQuote(c, d) != 0:
+			_, end = readQuoted(query, i, identQuote(c, d))
+		case c == '\'' || c == '"':
+			end = readString(query, i, c, d)
+		case commentEnd(query, i, d) > 0:
+			end = commentEnd(query, i, d)
+		case c == '?':
+			n++
+			s.WriteString(d.bindVar + strconv.Itoa(n))
+			i++
+			continue
+		}
+		s.WriteString(query[i:end])
+		i = end
+	}
+
+	return s.String()
+}
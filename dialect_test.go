@@ -0,0 +1,97 @@
+package sqlBuilder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDialect_Quote(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").Select("a`;drop").Where("id", 1).ToSql()
+	if sql == "SELECT `a``;drop` FROM `user` WHERE `id` = ?" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").SetDialect(Postgres).Table("users u").Select("u.id", `na"me`).ToSql()
+	if sql == `SELECT "u"."id","na""me" FROM "users" as "u"` &&
+		reflect.DeepEqual(params, []interface{}{}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").SetDialect(SQLServer).Select("count(id) c", "a]b").ToSql()
+	if sql == "SELECT count([id]) as [c],[a]]b] FROM [user]" &&
+		reflect.DeepEqual(params, []interface{}{}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestDialect_Rebind(t *testing.T) {
+	tests := []struct {
+		dialect *Dialect
+		sql     string
+		want    string
+	}{
+		{MySQL, "SELECT * FROM `user` WHERE `id` = ? AND `name` = ?", "SELECT * FROM `user` WHERE `id` = ? AND `name` = ?"},
+		{Postgres, `SELECT '?' FROM "a?" WHERE "id" = ? AND "name" IN (?,?) -- ?`, `SELECT '?' FROM "a?" WHERE "id" = $1 AND "name" IN ($2,$3) -- ?`},
+		{SQLServer, "UPDATE [a?] SET [n]=? WHERE [id] = ? /* ? */", "UPDATE [a?] SET [n]=@p1 WHERE [id] = @p2 /* ? */"},
+		{SQLite, `SELECT * FROM "user" WHERE "id" = ?`, `SELECT * FROM "user" WHERE "id" = ?`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.dialect.Rebind(tt.sql); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.dialect.Name, got, tt.want)
+		}
+	}
+}
+
+func TestBuilder_Strict(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	b := NewBuilder("user").Strict(true)
+	sql, params = b.Select("id", "count(*) as c").Where("name", "like", "%q%").ToSql()
+	if sql == "SELECT `id`,count(*) as `c` FROM `user` WHERE `name` like ?" &&
+		reflect.DeepEqual(params, []interface{}{"%q%"}) && b.Err() == nil {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params, b.Err())
+	}
+
+	sql, params = b.Where("a` OR 1=1 -- ", 1).ToSql()
+	if sql == "" && params == nil && errors.Is(b.Err(), ErrInvalidIdentifier) {
+		t.Log(b.Err())
+	} else {
+		t.Error(sql, params, b.Err())
+	}
+
+	sql, params = b.Where("id", "IN", func(m *Builder) {
+		m.Table("user_old").Select("id;drop")
+	}).ToSql()
+	if sql == "" && params == nil && errors.Is(b.Err(), ErrInvalidIdentifier) {
+		t.Log(b.Err())
+	} else {
+		t.Error(sql, params, b.Err())
+	}
+
+	sql, params = b.Where("id", 1).Update(map[string]interface{}{"name": "test"})
+	if sql == "UPDATE `user` SET `name`=? WHERE `id` = ?" &&
+		reflect.DeepEqual(params, []interface{}{"test", 1}) && b.Err() == nil {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params, b.Err())
+	}
+}
@@ -0,0 +1,28 @@
+package sqlBuilder
+
+import "errors"
+
+var (
+	// ErrInvalidIdentifier 严格模式下标识符含有不安全字符
+	ErrInvalidIdentifier = errors.New("sqlBuilder: invalid identifier")
+	// ErrTooManyPlaceholders 单条语句的绑定参数超过 MaxPlaceholders 限制
+	ErrTooManyPlaceholders = errors.New("sqlBuilder: too many placeholders")
+	// ErrSoftDeleteDisabled 表未开启软删除
+	ErrSoftDeleteDisabled = errors.New("sqlBuilder: soft delete is not enabled")
+	// ErrConflictColumnsRequired 当前方言的 upsert 需要指定冲突字段
+	ErrConflictColumnsRequired = errors.New("sqlBuilder: conflict columns required")
+	// ErrInvalidSubQuery 子查询类型不支持
+	ErrInvalidSubQuery = errors.New("sqlBuilder: invalid sub query")
+	// ErrNoExecutor 未指定执行SQL的数据库连接
+	ErrNoExecutor = errors.New("sqlBuilder: no executor")
+	// ErrTxUnsupported 数据库连接不支持开启事务
+	ErrTxUnsupported = errors.New("sqlBuilder: executor does not support transactions")
+	// ErrInvalidRows 批量插入的数据为空或类型不支持
+	ErrInvalidRows = errors.New("sqlBuilder: invalid rows")
+	// ErrStaleRecord 乐观锁更新时记录已被修改
+	ErrStaleRecord = errors.New("sqlBuilder: stale record")
+	// ErrLimitUnsupported 当前方言的更新和删除语句不支持 LIMIT
+	ErrLimitUnsupported = errors.New("sqlBuilder: limit is not supported")
+	// ErrExplainUnsupported 当前方言不支持 Explain
+	ErrExplainUnsupported = errors.New("sqlBuilder: explain is not supported")
+)
@@ -2,11 +2,25 @@ package sqlBuilder
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 )
 
+// Delete 删除记录，表开启软删除时改为更新软删除字段
 func (b *Builder) Delete() (string, []interface{}) {
-	defer b.cleanLastSql()
+	return b.build("DELETE", func() (string, []interface{}) {
+		if column := softDeleteColumn(b.scopeTable()); column != "" {
+			return b.softDeleteSql(column, b.GetDialect().now)
+		}
+
+		return b.deleteSql()
+	})
+}
+
+func (b *Builder) deleteSql() (string, []interface{}) {
+	b.applyGlobalScopes()
 
 	params := make([]interface{}, 0)
 
@@ -17,9 +31,7 @@ func (b *Builder) Delete() (string, []interface{}) {
 		sql += " ORDER BY " + strings.Join(b.methods.order, ",")
 	}
 
-	if b.methods.limit != "" {
-		sql += b.methods.limit
-	}
+	sql = b.builderMutationLimit(sql)
 
 	params = append(params, whereParams...)
 
@@ -31,41 +43,198 @@ func (b *Builder) DuplicateKey(duplicateKey map[string]interface{}) *Builder {
 	return b
 }
 
+// DuplicateKeyAlias 为插入的新行指定别名（MySQL 8.0.19+），DuplicateKey 中可以通过 Raw("alias.col") 引用新值
+func (b *Builder) DuplicateKeyAlias(alias string) *Builder {
+	b.methods.duplicateKeyAlias = alias
+	return b
+}
+
+// Values 在 DuplicateKey 中引用插入的新值，即 VALUES(`col`)
+func Values(column string) Raw {
+	return Raw("VALUES(" + MySQL.Quote(column) + ")")
+}
+
+// assignValue 返回赋值表达式右侧的SQL和绑定参数，Raw 和 RawExpr 原样输出
+func assignValue(v interface{}) (string, []interface{}) {
+	switch v := v.(type) {
+	case Raw:
+		return string(v), nil
+	case RawExpr:
+		return v.Sql, v.Params
+	}
+	return "?", []interface{}{v}
+}
+
 func (b *Builder) Insert(args ...interface{}) (string, []interface{}) {
-	return b.insertReplace("INSERT", args...)
+	return b.build("INSERT", func() (string, []interface{}) {
+		return b.insertReplace("INSERT", args...)
+	})
 }
 
 func (b *Builder) Replace(args ...interface{}) (string, []interface{}) {
-	return b.insertReplace("REPLACE", args...)
+	return b.build("REPLACE", func() (string, []interface{}) {
+		return b.insertReplace("REPLACE", args...)
+	})
 }
 
-func (b *Builder) insertReplace(mode string, args ...interface{}) (string, []interface{}) {
-	params := make([]interface{}, 0)
-	sql := ""
+// InsertBatches 将多行数据按 size 行一批拆分为多条插入语句，每批的占位符数量不超过 MaxPlaceholders 限制
+func (b *Builder) InsertBatches(size int, rows ...map[string]interface{}) []Statement {
 	defer b.cleanLastSql()
 
-	var field []string
-	var values [][]interface{}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if len(rows[0]) == 0 {
+		b.setErr(ErrInvalidRows)
+		return nil
+	}
+
+	if limit := b.placeholderLimit(); limit > 0 {
+		// 自动维护的时间字段也占用占位符
+		now := b.now()
+		perRow := len(b.touch(rows[0], now, true))
+		if b.methods.duplicateKey != nil {
+			limit -= len(b.touch(b.methods.duplicateKey, now, false))
+		}
+		if maxRows := limit / perRow; maxRows > 0 && (size <= 0 || size > maxRows) {
+			size = maxRows
+		}
+	}
+
+	if size <= 0 {
+		size = len(rows)
+	}
+
+	statements := make([]Statement, 0, (len(rows)+size-1)/size)
+	for i := 0; i < len(rows); i += size {
+		chunk := rows[i:min(i+size, len(rows))]
+		args := make([]interface{}, len(chunk))
+		for k, row := range chunk {
+			args[k] = row
+		}
+
+		bw := b.Clone()
+		sql, params := bw.build("INSERT", func() (string, []interface{}) {
+			return bw.insertReplace("INSERT", args...)
+		})
+		if err := bw.Err(); err != nil {
+			b.setErr(err)
+			return nil
+		}
+		statements = append(statements, Statement{Sql: sql, Params: params})
+	}
+
+	return statements
+}
+
+func (b *Builder) insertReplace(mode string, args ...interface{}) (string, []interface{}) {
 	if len(args) == 2 {
 		field, ok := args[0].([]string)
 		if query, ok1 := args[1].(func(*Builder)); ok && ok1 {
-			bw := NewBuilder("")
-			query(bw)
-			sql, params := bw.ToSql()
-			sql = fmt.Sprintf("%s INTO %s (%s) %s", mode, b.GetTable(), b.escapeId(field), sql)
-
-			return sql, params
+			return b.insertUsing(mode, field, query)
 		}
 	}
 
+	return b.insertRowsSql(mode, args, nil)
+}
+
+// insertRowsSql 构造多行插入语句，leading 同 insertRows
+func (b *Builder) insertRowsSql(mode string, args []interface{}, leading []string) (string, []interface{}) {
+	now := b.now()
+	field, values := b.insertRows(args, now, leading)
+
+	valuesSql, params := b.insertValues(values)
+	sql := fmt.Sprintf("%s INTO %s (%s) VALUES%s", mode, b.GetTable(), b.escapeId(field), valuesSql)
+
+	if b.methods.duplicateKey != nil && b.methods.duplicateKeyAlias != "" {
+		sql += " AS " + b.quote(b.methods.duplicateKeyAlias)
+	}
+
+	sql, duplicateParams := b.builderDuplicateKey(sql, now)
+	params = append(params, duplicateParams...)
+
+	return sql, params
+}
+
+// InsertUsing 插入子查询的结果，query 支持 func(*Builder)、func() *Builder 和 *Builder，可以与 DuplicateKey 一起使用
+func (b *Builder) InsertUsing(columns []string, query interface{}) (string, []interface{}) {
+	return b.build("INSERT", func() (string, []interface{}) {
+		return b.insertUsing("INSERT", columns, query)
+	})
+}
+
+// ReplaceUsing 以 REPLACE 插入子查询的结果，参数同 InsertUsing
+func (b *Builder) ReplaceUsing(columns []string, query interface{}) (string, []interface{}) {
+	return b.build("REPLACE", func() (string, []interface{}) {
+		return b.insertUsing("REPLACE", columns, query)
+	})
+}
+
+// insertUsing 构造插入子查询结果的语句，外层构造器的 With 在 MySQL 中放在 SELECT 之前，其他方言放在 INSERT 之前
+func (b *Builder) insertUsing(mode string, columns []string, query interface{}) (string, []interface{}) {
+	b.initialize()
+
+	querySql, queryParams := b.subQuery(query)
+
+	mysql := b.GetDialect().Name == MySQL.Name
+	if mysql {
+		// MySQL 只支持 INSERT ... WITH ... SELECT
+		var withParams []interface{}
+		querySql, withParams = b.builderWith(querySql)
+		queryParams = slices.Concat(withParams, queryParams)
+	}
+
+	params := slices.Concat(b.params["table"], queryParams)
+	sql := fmt.Sprintf("%s INTO %s (%s) %s", mode, b.GetTable(), b.escapeId(columns), querySql)
+
+	if !mysql {
+		var withParams []interface{}
+		sql, withParams = b.builderWith(sql)
+		params = slices.Concat(withParams, params)
+	}
+
+	sql, duplicateParams := b.builderDuplicateKey(sql, b.now())
+	params = append(params, duplicateParams...)
+
+	return sql, params
+}
+
+func (b *Builder) builderDuplicateKey(sql string, now time.Time) (string, []interface{}) {
+	if b.methods.duplicateKey == nil {
+		return sql, nil
+	}
+
+	params := make([]interface{}, 0)
+
+	duplicateKey := ""
+	data := b.touch(b.methods.duplicateKey, now, false)
+	for _, k := range slices.Sorted(maps.Keys(data)) {
+		value, valueParams := assignValue(data[k])
+		duplicateKey += fmt.Sprintf("%s=%s,", b.quoteName(k), value)
+		params = append(params, valueParams...)
+	}
+	duplicateKey = strings.Trim(duplicateKey, ",")
+
+	return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", sql, duplicateKey), params
+}
+
+// insertRows 收集插入的字段和值，字段以第一行为准，缺少字段的行会被忽略；
+// 字段按 leading 在前、其余字段按名称排序的顺序排列
+func (b *Builder) insertRows(args []interface{}, now time.Time, leading []string) (field []string, values [][]interface{}) {
 	for k, arg := range args {
 		isContinue := false
 		if arg, ok := arg.(map[string]interface{}); ok {
+			arg = b.touch(arg, now, true)
 
 			if k == 0 {
-				for f, _ := range arg {
-					field = append(field, f)
+				for f := range arg {
+					if !slices.Contains(leading, f) {
+						field = append(field, f)
+					}
 				}
+				slices.Sort(field)
+				field = append(slices.Clone(leading), field...)
 			}
 
 			value := make([]interface{}, 0)
@@ -86,8 +255,13 @@ func (b *Builder) insertReplace(mode string, args ...interface{}) (string, []int
 		}
 	}
 
-	sql = fmt.Sprintf("%s INTO %s (%s) VALUES", mode, b.GetTable(), b.escapeId(field))
+	return field, values
+}
+
+func (b *Builder) insertValues(values [][]interface{}) (string, []interface{}) {
+	params := make([]interface{}, 0)
 
+	sql := ""
 	comma := ""
 	for k, value := range values {
 		if k > 0 {
@@ -97,27 +271,26 @@ func (b *Builder) insertReplace(mode string, args ...interface{}) (string, []int
 		params = append(params, value...)
 	}
 
-	if b.methods.duplicateKey != nil {
-		duplicateKey := ""
-		for k, value := range b.methods.duplicateKey {
-			duplicateKey += fmt.Sprintf("%s=?,", k)
-			params = append(params, value)
-		}
-		duplicateKey = strings.Trim(duplicateKey, ",")
-		sql = fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", sql, duplicateKey)
-	}
-
 	return sql, params
 }
 
 func (b *Builder) Update(data map[string]interface{}) (string, []interface{}) {
-	defer b.cleanLastSql()
+	return b.build("UPDATE", func() (string, []interface{}) {
+		return b.updateSql(data)
+	})
+}
+
+func (b *Builder) updateSql(data map[string]interface{}) (string, []interface{}) {
+	b.applyGlobalScopes()
+
+	data = b.lockVersion(b.touch(data, b.now(), false))
 
 	params := make([]interface{}, 0)
 	setVal := ""
-	for k, v := range data {
-		setVal += b.escapeId(k) + "=?,"
-		params = append(params, v)
+	for _, k := range slices.Sorted(maps.Keys(data)) {
+		value, valueParams := assignValue(data[k])
+		setVal += b.escapeId(k) + "=" + value + ","
+		params = append(params, valueParams...)
 	}
 
 	setVal = strings.Trim(setVal, ",")
@@ -128,3 +301,23 @@ func (b *Builder) Update(data map[string]interface{}) (string, []interface{}) {
 
 	return sql, params
 }
+
+// Increment 字段自增 n，extra 为同时更新的其他字段
+func (b *Builder) Increment(column string, n interface{}, extra ...map[string]interface{}) (string, []interface{}) {
+	return b.Update(b.step(column, "+", n, extra))
+}
+
+// Decrement 字段自减 n，extra 为同时更新的其他字段
+func (b *Builder) Decrement(column string, n interface{}, extra ...map[string]interface{}) (string, []interface{}) {
+	return b.Update(b.step(column, "-", n, extra))
+}
+
+func (b *Builder) step(column string, operator string, n interface{}, extra []map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, 1)
+	for _, v := range extra {
+		maps.Copy(data, v)
+	}
+	data[column] = Expr(fmt.Sprintf("%s %s ?", b.escapeId(column), operator), n)
+
+	return data
+}
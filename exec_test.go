@@ -1,6 +1,7 @@
 package sqlBuilder
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -135,12 +136,227 @@ func TestBuilder_DuplicateKey(t *testing.T) {
 		"age":  18,
 	})
 
-	if (sql == "INSERT INTO `user` (`name`,`age`) VALUES(?,?) ON DUPLICATE KEY UPDATE age=?" &&
+	if (sql == "INSERT INTO `user` (`name`,`age`) VALUES(?,?) ON DUPLICATE KEY UPDATE `age`=?" &&
 		reflect.DeepEqual(params, []interface{}{"张三", 18, 18})) ||
-		(sql == "INSERT INTO `user` (`age`,`name`) VALUES(?,?) ON DUPLICATE KEY UPDATE age=?" &&
+		(sql == "INSERT INTO `user` (`age`,`name`) VALUES(?,?) ON DUPLICATE KEY UPDATE `age`=?" &&
 			reflect.DeepEqual(params, []interface{}{18, "张三", 18})) {
 		t.Log(sql, params)
 	} else {
 		t.Error(sql, params)
 	}
+
+	sql, params = NewBuilder("user").DuplicateKey(map[string]interface{}{
+		"name": "李四",
+		"age":  20,
+		"sex":  1,
+	}).Insert(map[string]interface{}{
+		"id": 1,
+	})
+
+	if sql == "INSERT INTO `user` (`id`) VALUES(?) ON DUPLICATE KEY UPDATE `age`=?,`name`=?,`sex`=?" &&
+		reflect.DeepEqual(params, []interface{}{1, 20, "李四", 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").DuplicateKey(map[string]interface{}{
+		"x=1, y": 2,
+	}).Insert(map[string]interface{}{
+		"id": 1,
+	})
+
+	if sql == "INSERT INTO `user` (`id`) VALUES(?) ON DUPLICATE KEY UPDATE `x=1, y`=?" &&
+		reflect.DeepEqual(params, []interface{}{1, 2}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	b := NewBuilder("user").Strict(true)
+	sql, params = b.DuplicateKey(map[string]interface{}{
+		"x=1, y": 2,
+	}).Insert(map[string]interface{}{
+		"id": 1,
+	})
+
+	if sql == "" && errors.Is(b.Err(), ErrInvalidIdentifier) {
+		t.Log(b.Err())
+	} else {
+		t.Error(sql, params, b.Err())
+	}
+}
+
+func TestBuilder_InsertBatches(t *testing.T) {
+	rows := make([]map[string]interface{}, 0, 5)
+	for i := 1; i <= 5; i++ {
+		rows = append(rows, map[string]interface{}{"id": i})
+	}
+
+	statements := NewBuilder("user").InsertBatches(2, rows...)
+	if reflect.DeepEqual(statements, []Statement{
+		{Sql: "INSERT INTO `user` (`id`) VALUES(?),(?)", Params: []interface{}{1, 2}},
+		{Sql: "INSERT INTO `user` (`id`) VALUES(?),(?)", Params: []interface{}{3, 4}},
+		{Sql: "INSERT INTO `user` (`id`) VALUES(?)", Params: []interface{}{5}},
+	}) {
+		t.Log(statements)
+	} else {
+		t.Error(statements)
+	}
+
+	statements = NewBuilder("user").MaxPlaceholders(3).InsertBatches(0, rows...)
+	if len(statements) == 2 && reflect.DeepEqual(statements[1].Params, []interface{}{4, 5}) {
+		t.Log(statements)
+	} else {
+		t.Error(statements)
+	}
+
+	b := NewBuilder("user")
+	statements = b.InsertBatches(2, map[string]interface{}{})
+	if statements == nil && errors.Is(b.Err(), ErrInvalidRows) {
+		t.Log(b.Err())
+	} else {
+		t.Error(statements, b.Err())
+	}
+}
+
+func TestBuilder_MaxPlaceholders(t *testing.T) {
+	b := NewBuilder("user").MaxPlaceholders(2)
+	sql, params := b.Insert(map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2}, map[string]interface{}{"id": 3})
+	if sql == "" && params == nil && errors.Is(b.Err(), ErrTooManyPlaceholders) {
+		t.Log(b.Err())
+	} else {
+		t.Error(sql, params, b.Err())
+	}
+}
+
+func TestBuilder_Increment(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("goods").Where("id", 1).Decrement("stock", 1)
+	if sql == "UPDATE `goods` SET `stock`=`stock` - ? WHERE `id` = ?" &&
+		reflect.DeepEqual(params, []interface{}{1, 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("post").Where("id", 1).Increment("views", 5, map[string]interface{}{"hot": Raw("1")})
+	if (sql == "UPDATE `post` SET `views`=`views` + ?,`hot`=1 WHERE `id` = ?" ||
+		sql == "UPDATE `post` SET `hot`=1,`views`=`views` + ? WHERE `id` = ?") &&
+		reflect.DeepEqual(params, []interface{}{5, 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("post").Where("id", 1).Update(map[string]interface{}{
+		"score": Expr("`score` * ?", 2),
+	})
+	if sql == "UPDATE `post` SET `score`=`score` * ? WHERE `id` = ?" &&
+		reflect.DeepEqual(params, []interface{}{2, 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_DuplicateKey_Expr(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("stat").DuplicateKey(map[string]interface{}{
+		"total": Raw("total + " + string(Values("total"))),
+	}).Insert(map[string]interface{}{"total": 3})
+	if sql == "INSERT INTO `stat` (`total`) VALUES(?) ON DUPLICATE KEY UPDATE `total`=total + VALUES(`total`)" &&
+		reflect.DeepEqual(params, []interface{}{3}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("stat").DuplicateKeyAlias("new").DuplicateKey(map[string]interface{}{
+		"total": Expr("total + new.total + ?", 1),
+	}).Insert(map[string]interface{}{"total": 3})
+	if sql == "INSERT INTO `stat` (`total`) VALUES(?) AS `new` ON DUPLICATE KEY UPDATE `total`=total + new.total + ?" &&
+		reflect.DeepEqual(params, []interface{}{3, 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_InsertUsing(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	source := NewBuilder("user_old").
+		With("active", func(m *Builder) {
+			m.Table("user_log").Select("user_id").Where("created_at", ">", "2024-01-01")
+		}).
+		Select("id", "name").
+		WhereIn("id", func(m *Builder) {
+			m.Table("active").Select("user_id")
+		})
+
+	sql, params = NewBuilder("user").DuplicateKey(map[string]interface{}{
+		"name": Values("name"),
+	}).InsertUsing([]string{"id", "name"}, source)
+	if sql == "INSERT INTO `user` (`id`,`name`) WITH `active` AS (SELECT `user_id` FROM `user_log` WHERE `created_at` > ?) "+
+		"SELECT `id`,`name` FROM `user_old` WHERE `id` IN (SELECT `user_id` FROM `active`) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`)" &&
+		reflect.DeepEqual(params, []interface{}{"2024-01-01"}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").ReplaceUsing([]string{"id"}, func(m *Builder) {
+		m.Table("user_old").Select("id").Where("id", ">", 100)
+	})
+	if sql == "REPLACE INTO `user` (`id`) SELECT `id` FROM `user_old` WHERE `id` > ?" &&
+		reflect.DeepEqual(params, []interface{}{100}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	active := func(m *Builder) {
+		m.Table("user_log").Select("user_id").Where("created_at", ">", "2024-01-01")
+	}
+	sql, params = NewBuilder("user").With("active", active).InsertUsing([]string{"id"}, func(m *Builder) {
+		m.Table("active").Select("user_id").Where("user_id", ">", 100)
+	})
+	if sql == "INSERT INTO `user` (`id`) WITH `active` AS (SELECT `user_id` FROM `user_log` WHERE `created_at` > ?) "+
+		"SELECT `user_id` FROM `active` WHERE `user_id` > ?" &&
+		reflect.DeepEqual(params, []interface{}{"2024-01-01", 100}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").SetDialect(Postgres).With("active", active).InsertUsing([]string{"id"}, func(m *Builder) {
+		m.Table("active").Select("user_id").Where("user_id", ">", 100)
+	})
+	if sql == `WITH "active" AS (SELECT "user_id" FROM "user_log" WHERE "created_at" > ?) `+
+		`INSERT INTO "user" ("id") SELECT "user_id" FROM "active" WHERE "user_id" > ?` &&
+		reflect.DeepEqual(params, []interface{}{"2024-01-01", 100}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	b := NewBuilder("user")
+	sql, params = b.InsertUsing([]string{"id"}, "SELECT 1")
+	if sql == "" && errors.Is(b.Err(), ErrInvalidSubQuery) {
+		t.Log(b.Err())
+	} else {
+		t.Error(sql, params, b.Err())
+	}
 }
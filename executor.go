@@ -0,0 +1,96 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Executor 执行SQL的数据库连接，*sql.DB、*sql.Tx、*sql.Conn 均实现了该接口
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SetExecutor 指定执行SQL的数据库连接
+func (b *Builder) SetExecutor(executor Executor) *Builder {
+	b.executor = executor
+	return b
+}
+
+func (b *Builder) exec(ctx context.Context, query string, params []interface{}) (sql.Result, error) {
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+	if b.executor == nil {
+		return nil, ErrNoExecutor
+	}
+
+	return runExec(ctx, b.getHooks(), b.execEvent(query, params), func(ctx context.Context, query string, params []interface{}) (sql.Result, error) {
+		return b.executor.ExecContext(ctx, query, params...)
+	})
+}
+
+// execEvent 返回最近一次构造的语句，用于执行钩子
+func (b *Builder) execEvent(query string, params []interface{}) *QueryEvent {
+	event := &QueryEvent{Table: b.scopeTable(), Dialect: b.GetDialect()}
+	if b.lastEvent != nil {
+		*event = *b.lastEvent
+	}
+	event.Statement = Statement{Sql: query, Params: params}
+	return event
+}
+
+// QueryContext 执行查询
+func (b *Builder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	query, params := b.ToSql()
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+	if b.executor == nil {
+		return nil, ErrNoExecutor
+	}
+
+	var rows *sql.Rows
+	_, err := runExec(ctx, b.getHooks(), b.execEvent(query, params), func(ctx context.Context, query string, params []interface{}) (sql.Result, error) {
+		var err error
+		rows, err = b.executor.QueryContext(ctx, query, params...)
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// InsertContext 执行插入，参数同 Insert
+func (b *Builder) InsertContext(ctx context.Context, args ...interface{}) (sql.Result, error) {
+	query, params := b.Insert(args...)
+	return b.exec(ctx, query, params)
+}
+
+// UpdateContext 执行更新，通过 Version 指定版本号时影响行数为 0 返回 ErrStaleRecord
+func (b *Builder) UpdateContext(ctx context.Context, data map[string]interface{}) (sql.Result, error) {
+	locked := b.methods.version != nil && versionColumn(b.scopeTable()) != ""
+
+	query, params := b.Update(data)
+	result, err := b.exec(ctx, query, params)
+	if err != nil || !locked {
+		return result, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return result, err
+	}
+	if affected == 0 {
+		return result, ErrStaleRecord
+	}
+
+	return result, nil
+}
+
+// DeleteContext 执行删除
+func (b *Builder) DeleteContext(ctx context.Context) (sql.Result, error) {
+	query, params := b.Delete()
+	return b.exec(ctx, query, params)
+}
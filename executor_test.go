@@ -0,0 +1,281 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB 记录执行过的语句的测试数据库
+type fakeDB struct {
+	mu           sync.Mutex
+	log          []string
+	args         [][]interface{}
+	rowsAffected int64
+	columns      []string
+	rows         [][]driver.Value
+	// errs 按顺序返回的执行错误，为空时执行成功
+	errs []error
+}
+
+func (db *fakeDB) record(query string, args []driver.NamedValue) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	values := make([]interface{}, len(args))
+	for k, v := range args {
+		values[k] = v.Value
+	}
+	db.log = append(db.log, query)
+	db.args = append(db.args, values)
+
+	if len(db.errs) > 0 {
+		err := db.errs[0]
+		db.errs = db.errs[1:]
+		return err
+	}
+	return nil
+}
+
+func (db *fakeDB) queries() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return append([]string(nil), db.log...)
+}
+
+var (
+	fakeDBsMu sync.Mutex
+	fakeDBs   = make(map[string]*fakeDB)
+)
+
+type fakeDriver struct{}
+
+func init() {
+	sql.Register("sqlbuilder-fake", fakeDriver{})
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBsMu.Lock()
+	defer fakeDBsMu.Unlock()
+
+	return &fakeConn{db: fakeDBs[name]}, nil
+}
+
+// openFakeDB 打开一个新的测试数据库
+func openFakeDB(t *testing.T) (*sql.DB, *fakeDB) {
+	fake := &fakeDB{rowsAffected: 1}
+
+	fakeDBsMu.Lock()
+	name := fmt.Sprintf("%s-%d", t.Name(), len(fakeDBs))
+	fakeDBs[name] = fake
+	fakeDBsMu.Unlock()
+
+	db, err := sql.Open("sqlbuilder-fake", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return db, fake
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if err := c.db.record("PREPARE "+query, nil); err != nil {
+		return nil, err
+	}
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.db.record("BEGIN", nil); err != nil {
+		return nil, err
+	}
+	return &fakeTx{db: c.db}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.db.record(query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(c.db.rowsAffected), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.db.record(query, args); err != nil {
+		return nil, err
+	}
+	return &fakeRows{columns: c.db.columns, rows: c.db.rows}, nil
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx *fakeTx) Commit() error {
+	return tx.db.record("COMMIT", nil)
+}
+
+func (tx *fakeTx) Rollback() error {
+	return tx.db.record("ROLLBACK", nil)
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return strings.Count(s.query, "?")
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s *fakeStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *fakeStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestBuilder_Executor(t *testing.T) {
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	b := NewBuilder("user").SetExecutor(db)
+	if _, err := b.InsertContext(ctx, map[string]interface{}{"id": 1}); err != nil {
+		t.Error(err)
+	}
+	if _, err := b.Where("id", 1).DeleteContext(ctx); err != nil {
+		t.Error(err)
+	}
+
+	fake.columns = []string{"id"}
+	fake.rows = [][]driver.Value{{int64(1)}}
+	rows, err := b.Select("id").Where("id", 1).QueryContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id int64
+	for rows.Next() {
+		rows.Scan(&id)
+	}
+	rows.Close()
+
+	queries := fake.queries()
+	if id == 1 && reflect.DeepEqual(queries, []string{
+		"INSERT INTO `user` (`id`) VALUES(?)",
+		"delete from `user` WHERE `id` = ?",
+		"SELECT `id` FROM `user` WHERE `id` = ?",
+	}) {
+		t.Log(queries)
+	} else {
+		t.Error(id, queries)
+	}
+
+	if _, err := NewBuilder("user").Where("id", 1).DeleteContext(ctx); !errors.Is(err, ErrNoExecutor) {
+		t.Error(err)
+	}
+}
+
+// execSqlHook 记录钩子中看到的语句
+type execSqlHook struct {
+	NopHook
+	sqls *[]string
+}
+
+func (h execSqlHook) BeforeExec(ctx context.Context, e *QueryEvent) (context.Context, error) {
+	*h.sqls = append(*h.sqls, e.Sql)
+	return ctx, nil
+}
+
+func TestBuilder_Executor_Rebind(t *testing.T) {
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	var events []string
+	hook := execSqlHook{sqls: &events}
+
+	b := NewBuilder("user").SetDialect(Postgres).SetExecutor(db).Hook(hook)
+	if _, err := b.Where("id", 1).UpdateContext(ctx, map[string]interface{}{"name": "a"}); err != nil {
+		t.Error(err)
+	}
+	rows, err := b.Where("id", 1).QueryContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+
+	if _, err := NewBuilder("user").SetDialect(SQLServer).SetExecutor(db).Where("id", 1).DeleteContext(ctx); err != nil {
+		t.Error(err)
+	}
+
+	queries := fake.queries()
+	if reflect.DeepEqual(queries, []string{
+		`UPDATE "user" SET "name"=$1 WHERE "id" = $2`,
+		`SELECT * FROM "user" WHERE "id" = $1`,
+		"delete from [user] WHERE [id] = @p1",
+	}) && reflect.DeepEqual(events, []string{
+		`UPDATE "user" SET "name"=? WHERE "id" = ?`,
+		`SELECT * FROM "user" WHERE "id" = ?`,
+	}) {
+		t.Log(queries, events)
+	} else {
+		t.Error(queries, events)
+	}
+}
@@ -0,0 +1,501 @@
+package sqlBuilder
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// 执行计划警告类型
+const (
+	// WarningFullScan 全表扫描
+	WarningFullScan = "full_scan"
+	// WarningMissingIndex 带条件的全表扫描或自动创建的临时索引，通常说明缺少索引
+	WarningMissingIndex = "missing_index"
+	// WarningFilesort 无法使用索引排序
+	WarningFilesort = "filesort"
+	// WarningTemporary 使用临时表
+	WarningTemporary = "temporary"
+)
+
+// ExplainOptions Explain 选项，默认分析 ToSql 生成的查询
+type ExplainOptions struct {
+	// Analyze 实际执行语句并返回实际行数，分析 UPDATE、DELETE 时会真正修改数据
+	Analyze bool
+	// Update 不为 nil 时分析 Update(Update) 生成的语句
+	Update map[string]interface{}
+	// Delete 为 true 时分析 Delete() 生成的语句
+	Delete bool
+}
+
+// PlanNode 执行计划的节点
+type PlanNode struct {
+	// Operation 节点的操作，如 MySQL 的访问类型 ALL、ref，Postgres 的 Seq Scan，SQLite 的 SCAN
+	Operation  string
+	Table      string
+	Index      string
+	Condition  string
+	Rows       float64
+	ActualRows float64
+	Cost       float64
+	Children   []*PlanNode
+}
+
+// PlanWarning 执行计划中的潜在问题
+type PlanWarning struct {
+	Kind    string
+	Table   string
+	Message string
+}
+
+// Plan 解析后的执行计划
+type Plan struct {
+	Root     *PlanNode
+	Warnings []PlanWarning
+}
+
+func (p *Plan) warn(kind string, table string, message string) {
+	p.Warnings = append(p.Warnings, PlanWarning{Kind: kind, Table: table, Message: message})
+}
+
+// HasWarning 是否包含指定类型的警告
+func (p *Plan) HasWarning(kind string) bool {
+	return slices.ContainsFunc(p.Warnings, func(w PlanWarning) bool {
+		return w.Kind == kind
+	})
+}
+
+// ExplainSql 生成分析语句：MySQL 为 EXPLAIN FORMAT=JSON 或 EXPLAIN ANALYZE，
+// Postgres 为 EXPLAIN (FORMAT JSON) 或 EXPLAIN (ANALYZE, FORMAT JSON)，SQLite 为 EXPLAIN QUERY PLAN
+func (b *Builder) ExplainSql(opts ExplainOptions) (string, []interface{}) {
+	dialect := b.GetDialect()
+
+	var sql string
+	var params []interface{}
+	switch {
+	case opts.Update != nil:
+		sql, params = b.Update(opts.Update)
+	case opts.Delete:
+		sql, params = b.Delete()
+	default:
+		sql, params = b.ToSql()
+	}
+	if b.Err() != nil {
+		return "", nil
+	}
+
+	switch dialect.Name {
+	case MySQL.Name:
+		if opts.Analyze {
+			return "EXPLAIN ANALYZE " + sql, params
+		}
+		return "EXPLAIN FORMAT=JSON " + sql, params
+	case Postgres.Name:
+		if opts.Analyze {
+			return "EXPLAIN (ANALYZE, FORMAT JSON) " + sql, params
+		}
+		return "EXPLAIN (FORMAT JSON) " + sql, params
+	case SQLite.Name:
+		return "EXPLAIN QUERY PLAN " + sql, params
+	}
+
+	b.lastErr = fmt.Errorf("%w: %s", ErrExplainUnsupported, dialect.Name)
+	return "", nil
+}
+
+// Explain 执行分析语句并解析执行计划
+func (b *Builder) Explain(ctx context.Context, opts ExplainOptions) (*Plan, error) {
+	query, params := b.ExplainSql(opts)
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+	if b.executor == nil {
+		return nil, ErrNoExecutor
+	}
+
+	// 与 QueryContext 一样执行钩子，EXPLAIN ANALYZE 会真正执行更新和删除
+	var rows *sql.Rows
+	_, err := runExec(ctx, b.getHooks(), b.execEvent(query, params), func(ctx context.Context, query string, params []interface{}) (sql.Result, error) {
+		var err error
+		rows, err = b.executor.QueryContext(ctx, query, params...)
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	output, err := explainOutput(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePlan(b.GetDialect(), output)
+}
+
+// explainOutput 读取分析结果，多列以 | 分隔，多行以换行分隔
+func explainOutput(rows *sql.Rows) ([]byte, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0)
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for k := range values {
+		dest[k] = &values[k]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		line := make([]string, len(values))
+		for k, v := range values {
+			line[k] = v.String
+		}
+		lines = append(lines, strings.Join(line, "|"))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// ParsePlan 解析分析语句的输出：MySQL 的 JSON 或 EXPLAIN ANALYZE 树形文本，Postgres 的 JSON，
+// SQLite 的 EXPLAIN QUERY PLAN 结果（每行 id|parent|notused|detail，与 sqlite3 命令行输出一致）
+func ParsePlan(d *Dialect, output []byte) (*Plan, error) {
+	if d == nil {
+		d = MySQL
+	}
+
+	plan := &Plan{}
+	var err error
+	switch d.Name {
+	case MySQL.Name:
+		if trimmed := strings.TrimSpace(string(output)); strings.HasPrefix(trimmed, "{") {
+			err = plan.parseMySQLJSON(output)
+		} else {
+			plan.parseTree(trimmed)
+		}
+	case Postgres.Name:
+		err = plan.parsePostgres(output)
+	case SQLite.Name:
+		err = plan.parseSQLite(string(output))
+	default:
+		err = fmt.Errorf("%w: %s", ErrExplainUnsupported, d.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// mysqlNodes MySQL JSON 格式中作为子节点的字段
+var mysqlNodes = []string{
+	"query_block", "table", "ordering_operation", "grouping_operation", "duplicates_removal",
+	"windowing", "materialized_from_subquery", "union_result", "buffer_result",
+}
+
+func (p *Plan) parseMySQLJSON(output []byte) error {
+	var v map[string]interface{}
+	if err := json.Unmarshal(output, &v); err != nil {
+		return err
+	}
+
+	p.Root = &PlanNode{}
+	p.mysqlChildren(p.Root, v)
+	if len(p.Root.Children) == 1 {
+		p.Root = p.Root.Children[0]
+	}
+	return nil
+}
+
+func (p *Plan) mysqlNode(name string, v map[string]interface{}) *PlanNode {
+	node := &PlanNode{Operation: name}
+	if cost, ok := v["cost_info"].(map[string]interface{}); ok {
+		node.Cost = toFloat(cost["query_cost"])
+		if node.Cost == 0 {
+			node.Cost = toFloat(cost["prefix_cost"])
+		}
+	}
+
+	if name == "table" {
+		node.Operation, _ = v["access_type"].(string)
+		node.Table, _ = v["table_name"].(string)
+		node.Index, _ = v["key"].(string)
+		node.Condition, _ = v["attached_condition"].(string)
+		node.Rows = toFloat(v["rows_examined_per_scan"])
+
+		if node.Operation == "ALL" {
+			p.warn(WarningFullScan, node.Table, "full table scan on "+node.Table)
+			if _, ok := v["possible_keys"]; !ok && node.Condition != "" {
+				p.warn(WarningMissingIndex, node.Table, "no index for condition "+node.Condition)
+			}
+		}
+	}
+
+	if v["using_filesort"] == true {
+		p.warn(WarningFilesort, "", "using filesort in "+name)
+	}
+	if v["using_temporary_table"] == true {
+		p.warn(WarningTemporary, "", "using temporary table in "+name)
+	}
+
+	p.mysqlChildren(node, v)
+	return node
+}
+
+func (p *Plan) mysqlChildren(node *PlanNode, v map[string]interface{}) {
+	for _, key := range slices.Sorted(maps.Keys(v)) {
+		switch value := v[key].(type) {
+		case map[string]interface{}:
+			if slices.Contains(mysqlNodes, key) {
+				node.Children = append(node.Children, p.mysqlNode(key, value))
+			}
+		case []interface{}:
+			// nested_loop、attached_subqueries 等数组中的每一项包含 table 或 query_block
+			for _, item := range value {
+				if item, ok := item.(map[string]interface{}); ok {
+					p.mysqlChildren(node, item)
+				}
+			}
+		}
+	}
+}
+
+// parseTree 解析 MySQL EXPLAIN ANALYZE 的树形文本，每层缩进 4 个空格
+func (p *Plan) parseTree(output string) {
+	p.Root = &PlanNode{}
+	stack := []*PlanNode{p.Root}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		indent := strings.Index(line, "-> ")
+		if indent < 0 {
+			continue
+		}
+
+		text := line[indent+3:]
+		node := &PlanNode{Operation: text}
+		if i := strings.Index(text, "  ("); i >= 0 {
+			node.Operation = text[:i]
+			stats := text[i:]
+			node.Cost = treeStat(stats, "cost=")
+			node.Rows = treeStat(stats, "rows=")
+			if j := strings.Index(stats, "(actual"); j >= 0 {
+				node.ActualRows = treeStat(stats[j:], "rows=")
+			}
+		}
+
+		operation := node.Operation
+		node.Table, node.Index = treeAccess(operation)
+		if strings.HasPrefix(operation, "Table scan on ") && node.Table != "" {
+			p.warn(WarningFullScan, node.Table, "full table scan on "+node.Table)
+		}
+		if condition, ok := strings.CutPrefix(operation, "Filter: "); ok {
+			node.Condition = condition
+		}
+		if strings.HasPrefix(operation, "Sort") {
+			p.warn(WarningFilesort, "", operation)
+		}
+		if strings.HasPrefix(operation, "Temporary table") {
+			p.warn(WarningTemporary, "", operation)
+		}
+
+		depth := indent/4 + 1
+		if depth > len(stack) {
+			depth = len(stack)
+		}
+		stack = stack[:depth]
+		parent := stack[depth-1]
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, node)
+
+		// 全表扫描的父节点为过滤条件时说明条件没有可用的索引
+		if strings.HasPrefix(operation, "Table scan on ") && parent.Condition != "" {
+			p.warn(WarningMissingIndex, node.Table, "no index for condition "+parent.Condition)
+		}
+	}
+
+	if len(p.Root.Children) == 1 {
+		p.Root = p.Root.Children[0]
+	}
+}
+
+// treeAccess 解析 Table scan on t、Index lookup on t using idx 等访问方式中的表名和索引名，其他操作返回空
+func treeAccess(operation string) (table string, index string) {
+	head, rest, ok := strings.Cut(operation, " on ")
+	if !ok || strings.Contains(head, ":") || !strings.HasSuffix(head, "scan") && !strings.HasSuffix(head, "lookup") {
+		return "", ""
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	if len(fields) >= 3 && fields[1] == "using" {
+		return fields[0], fields[2]
+	}
+	return fields[0], ""
+}
+
+// treeStat 读取 key 后的数值
+func treeStat(s string, key string) float64 {
+	i := strings.Index(s, key)
+	if i < 0 {
+		return 0
+	}
+	s = s[i+len(key):]
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return r != '.' && r != 'e' && r != '+' && (r < '0' || r > '9')
+	})
+	if end >= 0 {
+		s = s[:end]
+	}
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func (p *Plan) parsePostgres(output []byte) error {
+	var v []struct {
+		Plan map[string]interface{} `json:"Plan"`
+	}
+	if err := json.Unmarshal(output, &v); err != nil {
+		return err
+	}
+	if len(v) == 0 || v[0].Plan == nil {
+		return fmt.Errorf("sqlBuilder: invalid postgres plan")
+	}
+
+	p.Root = p.postgresNode(v[0].Plan)
+	return nil
+}
+
+func (p *Plan) postgresNode(v map[string]interface{}) *PlanNode {
+	node := &PlanNode{
+		Rows:       toFloat(v["Plan Rows"]),
+		ActualRows: toFloat(v["Actual Rows"]),
+		Cost:       toFloat(v["Total Cost"]),
+	}
+	node.Operation, _ = v["Node Type"].(string)
+	node.Table, _ = v["Relation Name"].(string)
+	node.Index, _ = v["Index Name"].(string)
+	for _, key := range []string{"Filter", "Index Cond", "Hash Cond", "Join Filter"} {
+		if condition, ok := v[key].(string); ok {
+			node.Condition = condition
+			break
+		}
+	}
+
+	switch node.Operation {
+	case "Seq Scan":
+		p.warn(WarningFullScan, node.Table, "full table scan on "+node.Table)
+		if filter, ok := v["Filter"].(string); ok {
+			p.warn(WarningMissingIndex, node.Table, "no index for condition "+filter)
+		}
+	case "Sort", "Incremental Sort":
+		message := "sort without index"
+		if v["Sort Space Type"] == "Disk" {
+			message += " on disk"
+		}
+		p.warn(WarningFilesort, "", message)
+	}
+
+	if plans, ok := v["Plans"].([]interface{}); ok {
+		for _, child := range plans {
+			if child, ok := child.(map[string]interface{}); ok {
+				node.Children = append(node.Children, p.postgresNode(child))
+			}
+		}
+	}
+	return node
+}
+
+// parseSQLite 按 parent 组装 EXPLAIN QUERY PLAN 的结果
+func (p *Plan) parseSQLite(output string) error {
+	p.Root = &PlanNode{Operation: "QUERY PLAN"}
+	nodes := map[string]*PlanNode{"0": p.Root}
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "|", 4)
+		if len(fields) != 4 {
+			return fmt.Errorf("sqlBuilder: invalid sqlite plan row %q", line)
+		}
+
+		node := p.sqliteNode(fields[3])
+		parent, ok := nodes[fields[1]]
+		if !ok {
+			parent = p.Root
+		}
+		parent.Children = append(parent.Children, node)
+		nodes[fields[0]] = node
+	}
+
+	return nil
+}
+
+func (p *Plan) sqliteNode(detail string) *PlanNode {
+	node := &PlanNode{Operation: detail}
+	words := strings.Fields(detail)
+
+	if len(words) >= 2 && (words[0] == "SCAN" || words[0] == "SEARCH") {
+		node.Operation = words[0]
+		table := words[1:]
+		if table[0] == "TABLE" && len(table) > 1 {
+			table = table[1:]
+		}
+		node.Table = table[0]
+
+		if _, index, ok := strings.Cut(detail, " INDEX "); ok && !strings.HasPrefix(index, "(") {
+			if fields := strings.Fields(index); len(fields) > 0 {
+				node.Index = fields[0]
+			}
+		}
+		if _, condition, ok := strings.Cut(detail, " ("); ok {
+			node.Condition = strings.TrimSuffix(condition, ")")
+		}
+
+		if node.Operation == "SCAN" && node.Index == "" {
+			p.warn(WarningFullScan, node.Table, "full table scan on "+node.Table)
+		}
+		if strings.Contains(detail, "AUTOMATIC") {
+			p.warn(WarningMissingIndex, node.Table, detail)
+		}
+	}
+
+	if strings.HasPrefix(detail, "USE TEMP B-TREE FOR") {
+		if strings.HasSuffix(detail, "ORDER BY") {
+			p.warn(WarningFilesort, "", detail)
+		} else {
+			p.warn(WarningTemporary, "", detail)
+		}
+	}
+
+	return node
+}
+
+// toFloat 读取 JSON 中的数值，MySQL 的数值可能是字符串
+func toFloat(v interface{}) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
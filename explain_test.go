@@ -0,0 +1,204 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	data, err := os.ReadFile("testdata/explain/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func warningKinds(plan *Plan) []string {
+	kinds := make([]string, len(plan.Warnings))
+	for k, w := range plan.Warnings {
+		kinds[k] = w.Kind
+	}
+	return kinds
+}
+
+func TestParsePlan_MySQL(t *testing.T) {
+	plan, err := ParsePlan(MySQL, readFixture(t, "mysql.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := plan.Root
+	if root.Operation != "query_block" || root.Cost != 12.75 || len(root.Children) != 1 {
+		t.Fatal(root)
+	}
+	tables := root.Children[0].Children
+	if len(tables) == 2 &&
+		tables[0].Table == "user" && tables[0].Operation == "ALL" && tables[0].Rows == 100 && tables[0].Condition == "(`test`.`user`.`age` > 18)" &&
+		tables[1].Table == "order" && tables[1].Operation == "ref" && tables[1].Index == "idx_user_id" {
+		t.Log(tables[0], tables[1])
+	} else {
+		t.Error(tables)
+	}
+
+	if kinds := warningKinds(plan); !reflect.DeepEqual(kinds, []string{WarningFilesort, WarningFullScan, WarningMissingIndex}) {
+		t.Error(plan.Warnings)
+	}
+}
+
+func TestParsePlan_MySQLAnalyze(t *testing.T) {
+	plan, err := ParsePlan(MySQL, readFixture(t, "mysql_analyze.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort := plan.Root
+	if sort.Operation != "Sort: `user`.`name`" || sort.ActualRows != 12 || len(sort.Children) != 1 {
+		t.Fatal(sort)
+	}
+	filter := sort.Children[0]
+	if filter.Condition != "(`user`.age > 18)" || filter.Rows != 33.3 || len(filter.Children) != 1 {
+		t.Fatal(filter)
+	}
+	scan := filter.Children[0]
+	if scan.Table != "user" || scan.Cost != 10.25 || scan.Rows != 100 || scan.ActualRows != 100 {
+		t.Error(scan)
+	}
+
+	if kinds := warningKinds(plan); !reflect.DeepEqual(kinds, []string{WarningFilesort, WarningFullScan, WarningMissingIndex}) {
+		t.Error(plan.Warnings)
+	}
+}
+
+func TestParsePlan_MySQLAnalyzeFilter(t *testing.T) {
+	plan, err := ParsePlan(MySQL, readFixture(t, "mysql_analyze_filter.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	join := plan.Root
+	if join.Operation != "Nested loop inner join" || len(join.Children) != 2 {
+		t.Fatal(join)
+	}
+	filter, lookup := join.Children[0], join.Children[1]
+	if filter.Table != "" || filter.Index != "" || filter.Condition != "(t.note = 'paid using card on file')" || len(filter.Children) != 1 {
+		t.Error(filter)
+	}
+	if filter.Children[0].Table != "t" {
+		t.Error(filter.Children[0])
+	}
+	if lookup.Table != "u" || lookup.Index != "PRIMARY" {
+		t.Error(lookup)
+	}
+
+	if kinds := warningKinds(plan); !reflect.DeepEqual(kinds, []string{WarningFullScan, WarningMissingIndex}) {
+		t.Error(plan.Warnings)
+	}
+}
+
+func TestParsePlan_Postgres(t *testing.T) {
+	plan, err := ParsePlan(Postgres, readFixture(t, "postgres.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	join := plan.Root.Children[0]
+	scan := join.Children[1].Children[0]
+	if plan.Root.Operation == "Sort" && join.Condition == "(o.user_id = u.id)" &&
+		join.Children[0].Index == "order_user_id_idx" &&
+		scan.Operation == "Seq Scan" && scan.Table == "user" && scan.Rows == 33 && scan.ActualRows == 12 {
+		t.Log(plan.Root)
+	} else {
+		t.Error(plan.Root, join, scan)
+	}
+
+	if kinds := warningKinds(plan); !reflect.DeepEqual(kinds, []string{WarningFilesort, WarningFullScan, WarningMissingIndex}) {
+		t.Error(plan.Warnings)
+	}
+}
+
+func TestParsePlan_SQLite(t *testing.T) {
+	plan, err := ParsePlan(SQLite, readFixture(t, "sqlite.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := plan.Root.Children
+	if len(nodes) == 3 && nodes[0].Operation == "SCAN" && nodes[0].Table == "user" &&
+		nodes[1].Operation == "SEARCH" && nodes[1].Table == "order" && nodes[1].Index == "" && nodes[1].Condition == "user_id=?" {
+		t.Log(nodes[0], nodes[1])
+	} else {
+		t.Error(nodes)
+	}
+
+	if kinds := warningKinds(plan); !reflect.DeepEqual(kinds, []string{WarningFullScan, WarningMissingIndex, WarningFilesort}) {
+		t.Error(plan.Warnings)
+	}
+}
+
+func TestBuilder_ExplainSql(t *testing.T) {
+	tests := []struct {
+		builder *Builder
+		opts    ExplainOptions
+		want    string
+	}{
+		{NewBuilder("user").Where("id", 1), ExplainOptions{}, "EXPLAIN FORMAT=JSON SELECT * FROM `user` WHERE `id` = ?"},
+		{NewBuilder("user").Where("id", 1), ExplainOptions{Analyze: true}, "EXPLAIN ANALYZE SELECT * FROM `user` WHERE `id` = ?"},
+		{NewBuilder("user").SetDialect(Postgres).Where("id", 1), ExplainOptions{Delete: true}, `EXPLAIN (FORMAT JSON) delete from "user" WHERE "id" = ?`},
+		{NewBuilder("user").SetDialect(Postgres).Where("id", 1), ExplainOptions{Analyze: true, Update: map[string]interface{}{"age": 1}},
+			`EXPLAIN (ANALYZE, FORMAT JSON) UPDATE "user" SET "age"=? WHERE "id" = ?`},
+		{NewBuilder("user").SetDialect(SQLite).Where("id", 1), ExplainOptions{}, `EXPLAIN QUERY PLAN SELECT * FROM "user" WHERE "id" = ?`},
+	}
+
+	for _, test := range tests {
+		if sql, _ := test.builder.ExplainSql(test.opts); sql != test.want {
+			t.Error(sql)
+		}
+	}
+
+	b := NewBuilder("user").SetDialect(SQLServer)
+	if sql, _ := b.ExplainSql(ExplainOptions{}); sql != "" || !errors.Is(b.Err(), ErrExplainUnsupported) {
+		t.Error(sql, b.Err())
+	}
+}
+
+func TestBuilder_Explain(t *testing.T) {
+	db, fake := openFakeDB(t)
+	fake.columns = []string{"EXPLAIN"}
+	fake.rows = [][]driver.Value{{string(readFixture(t, "mysql.json"))}}
+
+	var events []string
+	hook := execSqlHook{sqls: &events}
+
+	plan, err := NewBuilder("user").SetExecutor(db).Hook(hook).Where("age", ">", 18).Explain(context.Background(), ExplainOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if queries := fake.queries(); queries[0] == "EXPLAIN FORMAT=JSON SELECT * FROM `user` WHERE `age` > ?" && plan.HasWarning(WarningFullScan) {
+		t.Log(plan.Warnings)
+	} else {
+		t.Error(queries, plan.Warnings)
+	}
+
+	// EXPLAIN ANALYZE 会执行更新，钩子和占位符转换同样生效
+	fake.rows = [][]driver.Value{{string(readFixture(t, "postgres.json"))}}
+	_, err = NewBuilder("user").SetDialect(Postgres).SetExecutor(db).Hook(hook).Where("id", 1).
+		Explain(context.Background(), ExplainOptions{Analyze: true, Update: map[string]interface{}{"age": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := fake.queries()
+	if queries[1] == `EXPLAIN (ANALYZE, FORMAT JSON) UPDATE "user" SET "age"=$1 WHERE "id" = $2` && reflect.DeepEqual(events, []string{
+		"EXPLAIN FORMAT=JSON SELECT * FROM `user` WHERE `age` > ?",
+		`EXPLAIN (ANALYZE, FORMAT JSON) UPDATE "user" SET "age"=? WHERE "id" = ?`,
+	}) {
+		t.Log(queries, events)
+	} else {
+		t.Error(queries, events)
+	}
+}
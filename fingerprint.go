@@ -0,0 +1,107 @@
+package sqlBuilder
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// fingerprintDialect 计算指纹时使用的方言，反引号和双引号都视为标识符引号
+var fingerprintDialect = &Dialect{quoteOpen: `"`, quoteClose: `"`}
+
+// Fingerprint 语句指纹：字面量替换为 ?，IN 列表折叠为 IN (?+)，折叠空白并统一关键字大小写后计算哈希。
+// 只有参数或 IN 列表长度不同的语句指纹相同。不确定方言时使用，反引号和双引号都视为标识符引号
+func Fingerprint(sql string) string {
+	return fingerprintDialect.Fingerprint(sql)
+}
+
+// Fingerprint 按方言计算语句指纹，MySQL 中双引号字符串同样替换为 ?，反斜杠转义按 MySQL 规则解析。
+// d 为 nil 时同 Fingerprint
+func (d *Dialect) Fingerprint(sql string) string {
+	if d == nil {
+		d = fingerprintDialect
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(normalize(sql, d)))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Fingerprint 返回当前查询的指纹，不会清空构造器的状态
+func (b *Builder) Fingerprint() string {
+	bw := b.Clone()
+	sql, _ := bw.subQuerySql()
+	if bw.Err() != nil {
+		return ""
+	}
+	return b.GetDialect().Fingerprint(sql)
+}
+
+// normalize 规范化语句，字面量替换为 ?，IN 列表折叠为 IN (?+)，未加引号的单词转为大写，连续空白折叠为一个空格
+func normalize(sql string, d *Dialect) string {
+	if d == nil {
+		d = MySQL
+	}
+
+	tokens := tokenize(sql, d)
+
+	var s strings.Builder
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.kind == tokenComment {
+			continue
+		}
+		if tok.space && s.Len() > 0 {
+			s.WriteByte(' ')
+		}
+
+		switch tok.kind {
+		case tokenString, tokenNumber:
+			s.WriteByte('?')
+		case tokenOperator:
+			if tok.text == "-" && i+1 < len(tokens) && tokens[i+1].kind == tokenNumber && !tokens[i+1].space &&
+				(i == 0 || !isExprEnd(tokens[i-1])) {
+				// 负数
+				continue
+			}
+			s.WriteString(tok.text)
+		case tokenQuotedIdent:
+			s.WriteString(tok.quoted(d))
+		case tokenIdent, tokenKeyword:
+			s.WriteString(strings.ToUpper(tok.text))
+			if strings.EqualFold(tok.text, "IN") {
+				if end := inListEnd(tokens, i+1); end > 0 {
+					if tokens[i+1].space {
+						s.WriteByte(' ')
+					}
+					s.WriteString("(?+)")
+					i = end
+				}
+			}
+		default:
+			s.WriteString(tok.text)
+		}
+	}
+
+	return s.String()
+}
+
+// inListEnd 判断 tokens[start:] 是否为只包含字面量和占位符的列表，返回右括号的位置，不是时返回 0
+func inListEnd(tokens []token, start int) int {
+	if start >= len(tokens) || tokens[start].text != "(" {
+		return 0
+	}
+
+	for i := start + 1; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.text == ")":
+			return i
+		case tok.text == "?", tok.text == ",", tok.text == "-",
+			tok.kind == tokenString, tok.kind == tokenNumber:
+		default:
+			return 0
+		}
+	}
+	return 0
+}
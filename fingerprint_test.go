@@ -0,0 +1,86 @@
+package sqlBuilder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"select  *  from `user` where `id` in (?,?,?) and name = 'a''b'":  "SELECT * FROM `user` WHERE `id` IN (?+) AND NAME = ?",
+		"SELECT * FROM \"user\" WHERE \"age\" > -18 AND \"id\" IN (1, 2)": "SELECT * FROM \"user\" WHERE \"age\" > ? AND \"id\" IN (?+)",
+		"SELECT `a`-1 FROM `t` WHERE `id` IN (SELECT `id` FROM `u`)":      "SELECT `a`-? FROM `t` WHERE `id` IN (SELECT `id` FROM `u`)",
+		"SELECT /* app */ * FROM `t` WHERE `note` = 'it''s' -- x\n":       "SELECT * FROM `t` WHERE `note` = ?",
+	}
+
+	for sql, want := range tests {
+		if got := normalize(sql, fingerprintDialect); got != want {
+			t.Error(sql, got)
+		}
+	}
+
+	if got := normalize("SELECT 'it\\'s', 1 # x", MySQL); got != "SELECT ?, ?" {
+		t.Error(got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := NewBuilder("user").WhereIn("id", []int{1, 2}).Where("name", "a")
+	b := NewBuilder("user").WhereIn("id", []int{1, 2, 3, 4}).Where("name", "b")
+	c := NewBuilder("user").WhereIn("id", []int{1}).Where("email", "a")
+
+	if a.Fingerprint() == b.Fingerprint() && a.Fingerprint() != c.Fingerprint() && len(a.Fingerprint()) == 16 {
+		t.Log(a.Fingerprint(), c.Fingerprint())
+	} else {
+		t.Error(a.Fingerprint(), b.Fingerprint(), c.Fingerprint())
+	}
+
+	// Fingerprint 不清空构造器的状态
+	sql, params := a.ToSql()
+	if sql == "SELECT * FROM `user` WHERE `id` IN (?,?) AND `name` = ?" && reflect.DeepEqual(params, []interface{}{1, 2, "a"}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	if Fingerprint("select * from `user` where id = 1") != Fingerprint("SELECT *\n  FROM `user`\n WHERE ID = 2") {
+		t.Error("whitespace and case")
+	}
+}
+
+func TestDialect_Fingerprint(t *testing.T) {
+	if MySQL.Fingerprint("SELECT * FROM `t` WHERE `a` = 'it\\'s' AND `b` = \"x\"") !=
+		MySQL.Fingerprint("SELECT * FROM `t` WHERE `a` = 'y' AND `b` = \"y\"") {
+		t.Error("mysql literals")
+	}
+
+	if Postgres.Fingerprint(`SELECT * FROM "t" WHERE "a" = 'x'`) == Postgres.Fingerprint(`SELECT * FROM "t" WHERE "b" = 'x'`) {
+		t.Error("postgres identifiers")
+	}
+
+	if (*Dialect)(nil).Fingerprint("SELECT 1") != Fingerprint("SELECT 1") {
+		t.Error("nil dialect")
+	}
+
+	a := NewBuilder("user").Where("name", "a").Where(Raw(`note = "x"`))
+	b := NewBuilder("user").Where("name", "b").Where(Raw(`note = "y"`))
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Log(a.Fingerprint())
+	} else {
+		t.Error(a.Fingerprint(), b.Fingerprint())
+	}
+}
+
+func TestBuilder_InsertColumnOrder(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		sql, params := NewBuilder("user").Insert(map[string]interface{}{"name": "a", "age": 18, "id": 1})
+		if sql != "INSERT INTO `user` (`age`,`id`,`name`) VALUES(?,?,?)" || !reflect.DeepEqual(params, []interface{}{18, 1, "a"}) {
+			t.Fatal(sql, params)
+		}
+
+		sql, params = NewBuilder("user").Where("id", 1).Update(map[string]interface{}{"name": "a", "age": 18})
+		if sql != "UPDATE `user` SET `age`=?,`name`=? WHERE `id` = ?" || !reflect.DeepEqual(params, []interface{}{18, "a", 1}) {
+			t.Fatal(sql, params)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package sqlBuilder
+
+import (
+	"slices"
+	"strings"
+)
+
+// FormatOptions 格式化选项
+type FormatOptions struct {
+	// Indent 缩进，默认为两个空格
+	Indent string
+	// Dialect 解析标识符引号使用的方言，默认为 MySQL
+	Dialect *Dialect
+	// Uppercase 关键字转为大写
+	Uppercase bool
+}
+
+// clauseWords 在当前层级的顶层出现时另起一行的子句关键字
+var clauseWords = []string{
+	"SELECT", "FROM", "WHERE", "GROUP", "HAVING", "ORDER", "LIMIT", "OFFSET", "UNION", "VALUES", "SET",
+	"JOIN", "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "ON", "USING", "WHEN", "RETURNING",
+	"INSERT", "REPLACE", "UPDATE", "DELETE",
+}
+
+// upperWords Uppercase 时转为大写的其他关键字
+var upperWords = []string{
+	"INTO", "DUPLICATE", "KEY", "CONFLICT", "DO", "NOTHING",
+	"EXISTS", "WITH", "OUTER", "MERGE", "MATCHED", "IGNORE",
+}
+
+// formatLevel 一层子查询的格式化状态
+type formatLevel struct {
+	// base 子句的缩进层级，closing 右括号的缩进层级
+	base    int
+	closing int
+	clause  string
+	plain   int // 未闭合的普通括号数量
+	between bool
+}
+
+// Format 格式化SQL：子句另起一行，子查询缩进，WHERE、HAVING 中的 AND、OR 条件换行对齐
+func Format(sql string, opts FormatOptions) string {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	if opts.Dialect == nil {
+		opts.Dialect = MySQL
+	}
+
+	tokens := tokenize(sql, opts.Dialect)
+	f := &formatter{indent: opts.Indent, dialect: opts.Dialect, pending: -1}
+	levels := []*formatLevel{{}}
+
+	for i, tok := range tokens {
+		level := levels[len(levels)-1]
+		depth := len(levels) - 1
+
+		word := ""
+		if tok.kind == tokenIdent || tok.kind == tokenKeyword {
+			word = strings.ToUpper(tok.text)
+		}
+		isClause := word != "" && slices.Contains(clauseWords, word) && isClauseStart(tokens, i, word)
+
+		switch {
+		case tok.text == "(" && nextWord(tokens, i, "SELECT", "WITH"):
+			f.write(tok)
+			levels = append(levels, &formatLevel{base: f.line + 1, closing: f.line})
+			f.newline(f.line + 1)
+			continue
+		case tok.text == "(":
+			level.plain++
+		case tok.text == ")" && level.plain == 0 && depth > 0:
+			levels = levels[:depth]
+			f.newline(level.closing)
+		case tok.text == ")":
+			level.plain--
+		case level.plain > 0 || word == "":
+		case word == "BETWEEN":
+			level.between = true
+		case word == "AND" && level.between:
+			level.between = false
+		case (word == "AND" || word == "OR") && (level.clause == "WHERE" || level.clause == "HAVING"):
+			f.newline(level.base + 1)
+		case isClause:
+			if i > 0 {
+				f.newline(level.base)
+			}
+			level.clause = word
+		}
+
+		if opts.Uppercase && word != "" && (tok.kind == tokenKeyword || slices.Contains(clauseWords, word) || slices.Contains(upperWords, word)) {
+			tok.text = word
+		}
+		f.write(tok)
+	}
+
+	return f.s.String()
+}
+
+// nextWord 判断 tokens[i] 的下一个词法单元是否为 words 之一
+func nextWord(tokens []token, i int, words ...string) bool {
+	return i+1 < len(tokens) && tokens[i+1].kind != tokenQuotedIdent && slices.Contains(words, strings.ToUpper(tokens[i+1].text))
+}
+
+// prevWord 判断 tokens[i] 的上一个词法单元是否为 words 之一
+func prevWord(tokens []token, i int, words ...string) bool {
+	return i > 0 && tokens[i-1].kind != tokenQuotedIdent && slices.Contains(words, strings.ToUpper(tokens[i-1].text))
+}
+
+// isClauseStart 判断子句关键字是否位于子句的开头，排除 DELETE FROM、LEFT() 函数、ON 连接条件、CASE WHEN、
+// VALUES() 函数、ON DUPLICATE KEY UPDATE 等
+func isClauseStart(tokens []token, i int, word string) bool {
+	switch word {
+	case "VALUES":
+		return i == 0 || tokens[i-1].kind != tokenOperator && tokens[i-1].text != "," && tokens[i-1].text != "("
+	case "INSERT", "REPLACE", "UPDATE", "DELETE":
+		return !prevWord(tokens, i, "KEY", "DO", "THEN", "FOR") && !nextWord(tokens, i, "(")
+	case "FROM":
+		return !prevWord(tokens, i, "DELETE")
+	case "GROUP", "ORDER":
+		return nextWord(tokens, i, "BY")
+	case "JOIN":
+		return !prevWord(tokens, i, "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "OUTER")
+	case "INNER", "LEFT", "RIGHT", "FULL", "CROSS":
+		return nextWord(tokens, i, "JOIN", "OUTER")
+	case "ON":
+		return nextWord(tokens, i, "DUPLICATE", "CONFLICT")
+	case "WHEN":
+		return nextWord(tokens, i, "MATCHED", "NOT")
+	case "USING":
+		return nextWord(tokens, i, "(")
+	}
+	return true
+}
+
+// formatter 按行输出词法单元
+type formatter struct {
+	indent  string
+	dialect *Dialect
+	s       strings.Builder
+	// pending 下一个词法单元前需要换行时的缩进层级，-1 表示不换行
+	pending int
+	// line 当前行的缩进层级
+	line int
+}
+
+func (f *formatter) newline(level int) {
+	f.pending, f.line = level, level
+}
+
+func (f *formatter) write(tok token) {
+	if f.pending >= 0 {
+		f.s.WriteString("\n" + strings.Repeat(f.indent, f.pending))
+		f.pending = -1
+	} else if tok.space && f.s.Len() > 0 {
+		f.s.WriteByte(' ')
+	}
+
+	if tok.kind == tokenQuotedIdent {
+		f.s.WriteString(tok.quoted(f.dialect))
+		return
+	}
+	f.s.WriteString(tok.text)
+
+	// 单行注释之后的内容必须另起一行
+	if tok.isLineComment() {
+		f.pending = f.line
+	}
+}
+
+// ToPrettySql 构造查询并格式化，参数同 ToSql
+func (b *Builder) ToPrettySql() (string, []interface{}) {
+	sql, params := b.ToSql()
+	if sql == "" {
+		return sql, params
+	}
+	return Format(sql, FormatOptions{Dialect: b.GetDialect()}), params
+}
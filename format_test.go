@@ -0,0 +1,90 @@
+package sqlBuilder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_ToPrettySql(t *testing.T) {
+	sql, params := NewBuilder("user").Select("id", "name").
+		Table(func(b *Builder) { b.Table("user").Where("status", 1) }).
+		LefJoin("order as o", "ON o.user_id = tmp1.id").
+		Where("age", "BETWEEN", 18, 30).
+		Where(func(b *Builder) { b.Where("vip", 1).OrWhere("score", ">", 100) }).
+		WhereExists(func(b *Builder) { b.Table("post").Where("post.user_id", 1) }).
+		Group("id").Having("count(*)", ">", 1).Order("id", "desc").Limit(10).ToPrettySql()
+
+	want := "SELECT `id`,`name`\n" +
+		"FROM (\n" +
+		"  SELECT *\n" +
+		"  FROM `user`\n" +
+		"  WHERE `status` = ?\n" +
+		") as `tmp1`\n" +
+		"LEFT JOIN `order` as `o` ON o.user_id = tmp1.id\n" +
+		"WHERE `age` BETWEEN ? AND ?\n" +
+		"  AND (`vip` = ? OR `score` > ?)\n" +
+		"  AND EXISTS (\n" +
+		"    SELECT *\n" +
+		"    FROM `post`\n" +
+		"    WHERE `post`.`user_id` = ?\n" +
+		"  )\n" +
+		"GROUP BY `id`\n" +
+		"HAVING count(*) > ?\n" +
+		"ORDER BY `id` DESC\n" +
+		"LIMIT 10"
+	if sql == want && reflect.DeepEqual(params, []interface{}{1, 18, 30, 1, 100, 1, 1}) {
+		t.Log("\n" + sql)
+	} else {
+		t.Error("\n"+sql, params)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		sql  string
+		opts FormatOptions
+		want string
+	}{
+		{
+			"delete from `user` WHERE `id` = ? and `name` like ? ORDER BY `id` LIMIT 1",
+			FormatOptions{Uppercase: true},
+			"DELETE FROM `user`\nWHERE `id` = ?\n  AND `name` LIKE ?\nORDER BY `id`\nLIMIT 1",
+		},
+		{
+			"INSERT INTO `user` (`id`,`name`) VALUES(?,?),(?,?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`)",
+			FormatOptions{},
+			"INSERT INTO `user` (`id`,`name`)\nVALUES(?,?),(?,?)\nON DUPLICATE KEY UPDATE `name`=VALUES(`name`)",
+		},
+		{
+			`WITH "t" AS (SELECT "id" FROM "user" WHERE "age" > ?) UPDATE "user" SET "vip"=? WHERE "id" IN (SELECT "id" FROM "t")`,
+			FormatOptions{Dialect: Postgres, Indent: "\t"},
+			"WITH \"t\" AS (\n\tSELECT \"id\"\n\tFROM \"user\"\n\tWHERE \"age\" > ?\n)\nUPDATE \"user\"\nSET \"vip\"=?\nWHERE \"id\" IN (\n\tSELECT \"id\"\n\tFROM \"t\"\n)",
+		},
+		{
+			"SELECT LEFT(`name`, 1), EXTRACT(YEAR FROM `created_at`) FROM `user`",
+			FormatOptions{},
+			"SELECT LEFT(`name`, 1), EXTRACT(YEAR FROM `created_at`)\nFROM `user`",
+		},
+		{
+			"SELECT * FROM `t` WHERE a = 1 -- comment AND b\nAND c = 2",
+			FormatOptions{},
+			"SELECT *\nFROM `t`\nWHERE a = 1 -- comment AND b\n  AND c = 2",
+		},
+		{
+			"SELECT id, # note\nname /* FROM x */ FROM `t` WHERE `note` = 'it\\'s -- not a comment' OR b = 2",
+			FormatOptions{},
+			"SELECT id, # note\nname /* FROM x */\nFROM `t`\nWHERE `note` = 'it\\'s -- not a comment'\n  OR b = 2",
+		},
+		{
+			"SELECT 'a\\' AS \"x\" FROM t -- end",
+			FormatOptions{Dialect: Postgres},
+			"SELECT 'a\\' AS \"x\"\nFROM t -- end",
+		},
+	}
+
+	for _, test := range tests {
+		if got := Format(test.sql, test.opts); got != test.want {
+			t.Errorf("\n%s\n%s", got, test.want)
+		}
+	}
+}
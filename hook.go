@@ -0,0 +1,170 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+	"time"
+)
+
+// QueryEvent 钩子处理的语句，Sql 和 Params 在 AfterBuild、BeforeExec 中可以被改写
+type QueryEvent struct {
+	// Operation 语句类型：SELECT、INSERT、REPLACE、UPDATE、DELETE
+	Operation string
+	Table     string
+	Dialect   *Dialect
+	Statement
+}
+
+// Hook 语句钩子，返回错误时中止构造或执行，该错误会作为构造或执行的错误返回
+type Hook interface {
+	// BeforeBuild 构造SQL前调用，可以通过 b 追加条件
+	BeforeBuild(b *Builder, e *QueryEvent) error
+	// AfterBuild 构造SQL后调用，e.Sql 和 e.Params 为构造结果
+	AfterBuild(e *QueryEvent) error
+	// BeforeExec 执行SQL前调用，返回的 ctx 会传给之后的钩子和数据库连接，例如携带追踪的 span
+	BeforeExec(ctx context.Context, e *QueryEvent) (context.Context, error)
+	// AfterExec 执行SQL后调用，查询语句的 result 为 nil。调用过 BeforeExec 的钩子一定会调用 AfterExec
+	AfterExec(ctx context.Context, e *QueryEvent, result sql.Result, err error, duration time.Duration)
+}
+
+// NopHook 不做任何处理的钩子，嵌入后只需要实现关心的方法
+type NopHook struct{}
+
+func (NopHook) BeforeBuild(b *Builder, e *QueryEvent) error { return nil }
+
+func (NopHook) AfterBuild(e *QueryEvent) error { return nil }
+
+func (NopHook) BeforeExec(ctx context.Context, e *QueryEvent) (context.Context, error) {
+	return ctx, nil
+}
+
+func (NopHook) AfterExec(ctx context.Context, e *QueryEvent, result sql.Result, err error, duration time.Duration) {
+}
+
+type globalHook struct {
+	name string
+	hook Hook
+}
+
+var (
+	globalHooksMu sync.RWMutex
+	globalHooks   []globalHook
+)
+
+// RegisterHook 注册全局钩子，所有构造器按注册顺序执行，同名钩子会被替换
+func RegisterHook(name string, hook Hook) {
+	globalHooksMu.Lock()
+	defer globalHooksMu.Unlock()
+
+	for k, v := range globalHooks {
+		if v.name == name {
+			globalHooks[k].hook = hook
+			return
+		}
+	}
+	globalHooks = append(globalHooks, globalHook{name: name, hook: hook})
+}
+
+// RemoveHook 移除全局钩子
+func RemoveHook(name string) {
+	globalHooksMu.Lock()
+	defer globalHooksMu.Unlock()
+
+	globalHooks = slices.DeleteFunc(globalHooks, func(v globalHook) bool {
+		return v.name == name
+	})
+}
+
+// Hook 为构造器添加钩子，在全局钩子之后执行
+func (b *Builder) Hook(hooks ...Hook) *Builder {
+	b.hooks = append(slices.Clip(b.hooks), hooks...)
+	return b
+}
+
+// getHooks 返回全局钩子和构造器的钩子
+func (b *Builder) getHooks() []Hook {
+	globalHooksMu.RLock()
+	defer globalHooksMu.RUnlock()
+
+	hooks := make([]Hook, 0, len(globalHooks)+len(b.hooks))
+	for _, v := range globalHooks {
+		hooks = append(hooks, v.hook)
+	}
+	return append(hooks, b.hooks...)
+}
+
+// build 执行钩子并构造SQL，build 中产生的错误和钩子返回的错误都会中止构造
+func (b *Builder) build(operation string, build func() (string, []interface{})) (string, []interface{}) {
+	hooks := b.getHooks()
+	event := &QueryEvent{Operation: operation, Table: b.scopeTable(), Dialect: b.GetDialect()}
+	b.lastEvent = event
+
+	where := len(b.methods.where)
+	for _, hook := range hooks {
+		if err := hook.BeforeBuild(b, event); err != nil {
+			b.setErr(err)
+			break
+		}
+	}
+	// 钩子追加了条件时，原有条件整体加括号
+	if where > 0 && len(b.methods.where) > where {
+		added := slices.Clone(b.methods.where[where:])
+		b.methods.where = b.methods.where[:where]
+		b.groupWhere()
+		b.methods.where = append(b.methods.where, added...)
+	}
+
+	func() {
+		defer b.cleanLastSql()
+		if b.methods.err == nil {
+			event.Sql, event.Params = build()
+		}
+		event.Sql, event.Params = b.result(event.Sql, event.Params)
+	}()
+	if b.lastErr != nil {
+		return "", nil
+	}
+
+	for _, hook := range hooks {
+		if err := hook.AfterBuild(event); err != nil {
+			b.lastErr = err
+			return "", nil
+		}
+	}
+
+	return event.Sql, event.Params
+}
+
+// runExec 执行钩子并通过 exec 执行 e 中的语句，语句的占位符按方言转换后再交给 exec
+func runExec(ctx context.Context, hooks []Hook, e *QueryEvent, exec func(ctx context.Context, query string, params []interface{}) (sql.Result, error)) (result sql.Result, err error) {
+	called := 0
+	defer func() {
+		for _, hook := range slices.Backward(hooks[:called]) {
+			hook.AfterExec(ctx, e, result, err, 0)
+		}
+	}()
+
+	for _, hook := range hooks {
+		called++
+		hookCtx, err := hook.BeforeExec(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		if hookCtx != nil {
+			ctx = hookCtx
+		}
+	}
+
+	start := time.Now()
+	result, err = exec(ctx, e.Dialect.Rebind(e.Sql), e.Params)
+	duration := time.Since(start)
+
+	for _, hook := range slices.Backward(hooks) {
+		hook.AfterExec(ctx, e, result, err, duration)
+	}
+	called = 0
+
+	return result, err
+}
@@ -0,0 +1,223 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordHook 记录调用顺序的钩子
+type recordHook struct {
+	name  string
+	calls *[]string
+}
+
+func (h recordHook) BeforeBuild(b *Builder, e *QueryEvent) error {
+	*h.calls = append(*h.calls, h.name+".BeforeBuild "+e.Operation+" "+e.Table)
+	return nil
+}
+
+func (h recordHook) AfterBuild(e *QueryEvent) error {
+	*h.calls = append(*h.calls, h.name+".AfterBuild "+e.Sql)
+	return nil
+}
+
+func (h recordHook) BeforeExec(ctx context.Context, e *QueryEvent) (context.Context, error) {
+	*h.calls = append(*h.calls, h.name+".BeforeExec")
+	return ctx, nil
+}
+
+func (h recordHook) AfterExec(ctx context.Context, e *QueryEvent, result sql.Result, err error, duration time.Duration) {
+	*h.calls = append(*h.calls, h.name+".AfterExec")
+}
+
+// tenantHook 为每条语句追加租户条件
+type tenantHook struct {
+	NopHook
+	tenant int
+}
+
+func (h tenantHook) BeforeBuild(b *Builder, e *QueryEvent) error {
+	if e.Operation != "INSERT" {
+		b.Where("tenant_id", h.tenant)
+	}
+	return nil
+}
+
+type rewriteHook struct {
+	NopHook
+}
+
+func (rewriteHook) AfterBuild(e *QueryEvent) error {
+	e.Sql = "/* app */ " + e.Sql
+	return nil
+}
+
+type abortHook struct {
+	NopHook
+	err error
+}
+
+func (h abortHook) BeforeExec(ctx context.Context, e *QueryEvent) (context.Context, error) {
+	if strings.HasPrefix(e.Sql, "delete") {
+		return ctx, h.err
+	}
+	return ctx, nil
+}
+
+func TestBuilder_Hook(t *testing.T) {
+	b := NewBuilder("user").Hook(tenantHook{tenant: 7}, rewriteHook{})
+
+	sql, params := b.Where("id", "IN", func(b *Builder) {
+		b.Table("order").Select("user_id")
+	}).ToSql()
+	if sql == "/* app */ SELECT * FROM `user` WHERE (`id` IN (SELECT `user_id` FROM `order`)) AND `tenant_id` = ?" && reflect.DeepEqual(params, []interface{}{7}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Insert(map[string]interface{}{"id": 1})
+	if sql == "/* app */ INSERT INTO `user` (`id`) VALUES(?)" && reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Where("a", 1).OrWhere("b", 2).Update(map[string]interface{}{"name": "x"})
+	if sql == "/* app */ UPDATE `user` SET `name`=? WHERE (`a` = ? OR `b` = ?) AND `tenant_id` = ?" &&
+		reflect.DeepEqual(params, []interface{}{"x", 1, 2, 7}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.ToSql()
+	if sql == "/* app */ SELECT * FROM `user` WHERE `tenant_id` = ?" && reflect.DeepEqual(params, []interface{}{7}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_HookBatches(t *testing.T) {
+	calls := make([]string, 0)
+	hook := recordHook{name: "batch", calls: &calls}
+	rows := []map[string]interface{}{{"id": 1}, {"id": 2}, {"id": 3}}
+
+	statements := NewBuilder("user").Hook(hook, rewriteHook{}).InsertBatches(2, rows...)
+	if len(statements) == 2 && statements[1].Sql == "/* app */ INSERT INTO `user` (`id`) VALUES(?)" &&
+		reflect.DeepEqual(calls, []string{
+			"batch.BeforeBuild INSERT user",
+			"batch.AfterBuild INSERT INTO `user` (`id`) VALUES(?),(?)",
+			"batch.BeforeBuild INSERT user",
+			"batch.AfterBuild INSERT INTO `user` (`id`) VALUES(?)",
+		}) {
+		t.Log(statements, calls)
+	} else {
+		t.Error(statements, calls)
+	}
+
+	db, fake := openFakeDB(t)
+	calls = calls[:0]
+	_, err := NewBulkInserter(NewBuilder("user").SetExecutor(db).Hook(hook, rewriteHook{})).
+		BatchRows(2).
+		Run(context.Background(), slices.Values(rows))
+	if err == nil && reflect.DeepEqual(calls, []string{
+		"batch.BeforeBuild INSERT user",
+		"batch.AfterBuild INSERT INTO `user` (`id`) VALUES(?),(?)",
+		"batch.BeforeExec",
+		"batch.AfterExec",
+		"batch.BeforeBuild INSERT user",
+		"batch.AfterBuild INSERT INTO `user` (`id`) VALUES(?)",
+		"batch.BeforeExec",
+		"batch.AfterExec",
+	}) && reflect.DeepEqual(fake.queries(), []string{
+		"/* app */ INSERT INTO `user` (`id`) VALUES(?),(?)",
+		"/* app */ INSERT INTO `user` (`id`) VALUES(?)",
+	}) {
+		t.Log(calls)
+	} else {
+		t.Error(err, calls, fake.queries())
+	}
+}
+
+type abortBuildHook struct {
+	NopHook
+	err error
+}
+
+func (h abortBuildHook) AfterBuild(e *QueryEvent) error {
+	return h.err
+}
+
+func TestBuilder_HookAbort(t *testing.T) {
+	errDenied := errors.New("denied")
+
+	b := NewBuilder("user").Hook(abortBuildHook{err: errDenied})
+	if sql, params := b.Where("id", 1).Delete(); sql != "" || params != nil || !errors.Is(b.Err(), errDenied) {
+		t.Error(sql, params, b.Err())
+	}
+
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	b = NewBuilder("user").SetExecutor(db).Hook(abortHook{err: errDenied})
+	if _, err := b.Where("id", 1).DeleteContext(ctx); !errors.Is(err, errDenied) {
+		t.Error(err)
+	}
+	if _, err := b.Where("id", 1).UpdateContext(ctx, map[string]interface{}{"name": "a"}); err != nil {
+		t.Error(err)
+	}
+
+	queries := fake.queries()
+	if reflect.DeepEqual(queries, []string{"UPDATE `user` SET `name`=? WHERE `id` = ?"}) {
+		t.Log(queries)
+	} else {
+		t.Error(queries)
+	}
+}
+
+func TestRegisterHook(t *testing.T) {
+	calls := make([]string, 0)
+	RegisterHook("record", recordHook{name: "global", calls: &calls})
+	defer RemoveHook("record")
+
+	db, _ := openFakeDB(t)
+	ctx := context.Background()
+
+	err := NewSession(db).Hook(recordHook{name: "session", calls: &calls}).Transaction(ctx, func(tx *Session) error {
+		_, err := tx.NewBuilder("user").Where("id", 1).DeleteContext(ctx)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reflect.DeepEqual(calls, []string{
+		"global.BeforeBuild DELETE user",
+		"session.BeforeBuild DELETE user",
+		"global.AfterBuild delete from `user` WHERE `id` = ?",
+		"session.AfterBuild delete from `user` WHERE `id` = ?",
+		"global.BeforeExec",
+		"session.BeforeExec",
+		"session.AfterExec",
+		"global.AfterExec",
+	}) {
+		t.Log(calls)
+	} else {
+		t.Error(calls)
+	}
+
+	RemoveHook("record")
+	calls = calls[:0]
+	NewBuilder("user").ToSql()
+	if len(calls) != 0 {
+		t.Error(calls)
+	}
+}
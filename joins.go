@@ -8,10 +8,10 @@ func (b *Builder) Joins(table interface{}, condition string, joinType string, pa
 	isClosure, table, param, tableAlias := b.setTable(table)
 
 	if isClosure == 0 {
-		table = fmt.Sprintf("`%s`", table)
+		table = b.quoteName(table.(string))
 
 		if tableAlias != "" {
-			table = fmt.Sprintf("%s as `%s`", table, tableAlias)
+			table = fmt.Sprintf("%s as %s", table, b.quote(tableAlias))
 		}
 	}
 
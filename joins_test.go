@@ -61,3 +61,21 @@ func TestBuilder_LeftJoin(t *testing.T) {
 		t.Error(sql, params)
 	}
 }
+
+func TestBuilder_Join_Schema(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").Select("u.id", "o.amount").Table("app.user u").
+		Join("billing.order o", "o.user_id=u.id").
+		ToSql()
+
+	if sql == "SELECT `u`.`id`,`o`.`amount` FROM `app`.`user` as `u` INNER JOIN `billing`.`order` as `o` o.user_id=u.id" &&
+		reflect.DeepEqual(params, []interface{}{}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
@@ -0,0 +1,301 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Redacted 日志中替换敏感参数的值
+const Redacted = "[REDACTED]"
+
+// LogHook 通过 log/slog 记录执行的语句
+type LogHook struct {
+	NopHook
+	logger        *slog.Logger
+	level         slog.Level
+	slowThreshold time.Duration
+	interpolate   bool
+	redact        map[string]bool
+}
+
+// NewLogHook 创建日志钩子，logger 为 nil 时使用 slog.Default()，默认以 Info 级别记录
+func NewLogHook(logger *slog.Logger) *LogHook {
+	return &LogHook{logger: logger, level: slog.LevelInfo, redact: make(map[string]bool)}
+}
+
+// Level 普通语句的日志级别
+func (h *LogHook) Level(level slog.Level) *LogHook {
+	h.level = level
+	return h
+}
+
+// SlowThreshold 执行时间达到 threshold 的语句以 Warn 级别记录，0 表示不区分慢查询
+func (h *LogHook) SlowThreshold(threshold time.Duration) *LogHook {
+	h.slowThreshold = threshold
+	return h
+}
+
+// Interpolate 记录将参数代入后的SQL，默认分别记录SQL和参数
+func (h *LogHook) Interpolate(interpolate bool) *LogHook {
+	h.interpolate = interpolate
+	return h
+}
+
+// Redact 指定敏感字段，不区分大小写，这些字段对应的参数在日志中替换为 Redacted
+func (h *LogHook) Redact(columns ...string) *LogHook {
+	for _, column := range columns {
+		h.redact[strings.ToLower(column)] = true
+	}
+	return h
+}
+
+func (h *LogHook) AfterExec(ctx context.Context, e *QueryEvent, result sql.Result, err error, duration time.Duration) {
+	logger := h.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	level, msg := h.level, "sql"
+	switch {
+	case err != nil:
+		level, msg = slog.LevelError, "sql error"
+	case h.slowThreshold > 0 && duration >= h.slowThreshold:
+		level, msg = slog.LevelWarn, "slow sql"
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	params := h.redactParams(e)
+	attrs := make([]slog.Attr, 0, 9)
+	if h.interpolate {
+		attrs = append(attrs, slog.String("sql", interpolate(e.Sql, params, e.Dialect)))
+	} else {
+		attrs = append(attrs, slog.String("sql", e.Sql), slog.Any("params", params))
+	}
+	attrs = append(attrs,
+		slog.String("operation", e.Operation),
+		slog.String("table", e.Table),
+		slog.Duration("duration", duration),
+		slog.String("fingerprint", e.Dialect.Fingerprint(e.Sql)),
+		slog.String("caller", caller()),
+	)
+	if result != nil {
+		if affected, err := result.RowsAffected(); err == nil {
+			attrs = append(attrs, slog.Int64("rows", affected))
+		}
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// redactParams 返回敏感字段替换为 Redacted 后的参数
+func (h *LogHook) redactParams(e *QueryEvent) []interface{} {
+	if len(h.redact) == 0 {
+		return e.Params
+	}
+
+	params := make([]interface{}, len(e.Params))
+	copy(params, e.Params)
+	for k, column := range paramColumns(e.Sql, e.Dialect) {
+		if k < len(params) && h.redact[strings.ToLower(column)] {
+			params[k] = Redacted
+		}
+	}
+	return params
+}
+
+// paramColumns 按顺序返回每个占位符对应的字段名：
+// INSERT 和 MERGE 的 VALUES 中按字段列表的位置对应，其他位置取占位符前最近的字段名
+func paramColumns(sql string, d *Dialect) []string {
+	if d == nil {
+		d = MySQL
+	}
+
+	tokens := tokenize(sql, d)
+	columns := make([]string, 0)
+
+	var (
+		insertColumns []string
+		collect       bool // 从第一个括号中收集 INSERT 的字段列表
+		seen          bool
+		valuesDepth   = -1 // VALUES 所在的括号层级，-1 表示不在 VALUES 中
+		depth, index  int
+		last          string
+	)
+	if len(tokens) > 0 {
+		switch strings.ToUpper(tokens[0].text) {
+		case "INSERT", "REPLACE":
+			insertColumns, collect = make([]string, 0), true
+		case "MERGE":
+			insertColumns = mergeColumns(tokens)
+		}
+	}
+
+	for k, tok := range tokens {
+		inValues := valuesDepth >= 0
+		switch {
+		case tok.text == "(":
+			depth++
+		case tok.text == ")":
+			depth--
+			if depth == valuesDepth {
+				index = 0
+			}
+		case tok.text == "," && inValues && depth == valuesDepth+1:
+			index++
+		case tok.text == "?":
+			column := last
+			if inValues && index < len(insertColumns) {
+				column = insertColumns[index]
+			}
+			columns = append(columns, column)
+		case tok.kind == tokenIdent && strings.EqualFold(tok.text, "VALUES") && insertColumns != nil && !seen:
+			valuesDepth, seen = depth, true
+		case tok.kind == tokenIdent || tok.kind == tokenQuotedIdent:
+			if k+1 < len(tokens) && (tokens[k+1].text == "(" || tokens[k+1].text == ".") {
+				continue
+			}
+			last = tok.text
+			if collect && !seen && depth == 1 {
+				insertColumns = append(insertColumns, tok.text)
+			}
+		}
+
+		if inValues && (depth < valuesDepth || depth == valuesDepth && tok.text != ")" && tok.text != ",") {
+			valuesDepth = -1
+		}
+	}
+
+	return columns
+}
+
+// mergeColumns 返回 MERGE ... USING (VALUES ...) AS source (字段列表) 中的字段列表
+func mergeColumns(tokens []token) []string {
+	depth := 0
+	for k, tok := range tokens {
+		switch tok.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth > 0 || k+3 >= len(tokens) || !strings.EqualFold(tokens[k+1].text, "AS") || tokens[k+3].text != "(" {
+				continue
+			}
+
+			columns := make([]string, 0)
+			for _, tok := range tokens[k+4:] {
+				if tok.text == ")" {
+					return columns
+				}
+				if tok.kind == tokenIdent || tok.kind == tokenQuotedIdent {
+					columns = append(columns, tok.text)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// interpolate 将参数代入SQL中的占位符，只用于日志
+func interpolate(sql string, params []interface{}, d *Dialect) string {
+	if d == nil {
+		d = MySQL
+	}
+
+	var s strings.Builder
+	n := 0
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case identQuote(c, d) != 0:
+			_, end := readQuoted(sql, i, identQuote(c, d))
+			s.WriteString(sql[i:end])
+			i = end
+			continue
+		case c == '\'':
+			end := readString(sql, i, c, d)
+			s.WriteString(sql[i:end])
+			i = end
+			continue
+		case c == '?' && n < len(params):
+			s.WriteString(literal(params[n]))
+			n++
+		default:
+			s.WriteByte(c)
+		}
+		i++
+	}
+
+	return s.String()
+}
+
+// literal 将参数格式化为SQL字面量
+func literal(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999") + "'"
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case fmt.Stringer:
+		return literal(v.String())
+	}
+	return fmt.Sprint(v)
+}
+
+// packageDir 本包源文件所在的目录，用于跳过包内的调用栈
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// isStdlib 根据函数的包路径判断是否为标准库，标准库包路径的第一段不含点号
+func isStdlib(function string) bool {
+	elem, _, ok := strings.Cut(function, "/")
+	if !ok {
+		elem, _, _ = strings.Cut(elem, ".")
+	}
+	return !strings.Contains(elem, ".") && elem != "main"
+}
+
+// caller 返回包外第一个调用者的 file:line，跳过本包和标准库的调用栈
+func caller() string {
+	pc := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(2, pc)])
+	for {
+		frame, more := frames.Next()
+		inPackage := filepath.Dir(frame.File) == packageDir && !strings.HasSuffix(frame.File, "_test.go")
+		if !inPackage && !isStdlib(frame.Function) {
+			return filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
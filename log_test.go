@@ -0,0 +1,99 @@
+package sqlBuilder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParamColumns(t *testing.T) {
+	tests := map[string][]string{
+		"INSERT INTO `user` (`name`,`password`) VALUES(?,?),(?,?)":                        {"name", "password", "name", "password"},
+		"INSERT INTO `user` (`id`,`token`) VALUES(?,?) ON DUPLICATE KEY UPDATE `token`=?": {"id", "token", "token"},
+		"UPDATE `user` SET `password`=?,`age`=`age` + ? WHERE `u`.`id` IN (?,?)":          {"password", "age", "id", "id"},
+		"SELECT * FROM `user` WHERE LOWER(`email`) = ? AND `age` > ?":                     {"email", "age"},
+	}
+
+	for sql, want := range tests {
+		if got := paramColumns(sql, MySQL); !reflect.DeepEqual(got, want) {
+			t.Error(sql, got)
+		}
+	}
+}
+
+func TestLogHook_RedactUpsert(t *testing.T) {
+	hook := NewLogHook(slog.Default()).Redact("password")
+	rows := []map[string]interface{}{{"id": 1, "password": "secret"}, {"id": 2, "password": "secret"}}
+
+	for _, d := range []*Dialect{MySQL, Postgres, SQLite, SQLServer} {
+		sql, params := NewBuilder("user").SetDialect(d).Upsert(rows, []string{"id"}, nil)
+		got := hook.redactParams(&QueryEvent{Statement: Statement{Sql: sql, Params: params}, Dialect: d})
+		if reflect.DeepEqual(got, []interface{}{1, Redacted, 2, Redacted}) {
+			t.Log(d.Name, sql, got)
+		} else {
+			t.Error(d.Name, sql, got)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	sql := interpolate("SELECT '?' FROM `a?` WHERE `name` = ? AND `id` IN (?,?) AND `deleted_at` IS ?",
+		[]interface{}{"O'Brien", 1, int64(2), nil}, MySQL)
+	if sql == "SELECT '?' FROM `a?` WHERE `name` = 'O''Brien' AND `id` IN (1,2) AND `deleted_at` IS NULL" {
+		t.Log(sql)
+	} else {
+		t.Error(sql)
+	}
+}
+
+func TestLogHook(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	hook := NewLogHook(logger).Redact("Password")
+	b := NewBuilder("user").SetExecutor(db).Hook(hook)
+	if _, err := b.InsertContext(ctx, map[string]interface{}{"password": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] == "INFO" && entry["sql"] == "INSERT INTO `user` (`password`) VALUES(?)" &&
+		reflect.DeepEqual(entry["params"], []interface{}{Redacted}) && entry["rows"] == float64(1) &&
+		entry["operation"] == "INSERT" && entry["table"] == "user" && entry["fingerprint"] != "" &&
+		strings.HasPrefix(entry["caller"].(string), "log_test.go:") {
+		t.Log(entry)
+	} else {
+		t.Error(entry)
+	}
+
+	buf.Reset()
+	hook.Interpolate(true).SlowThreshold(time.Nanosecond)
+	b.Where("id", 1).UpdateContext(ctx, map[string]interface{}{"password": "secret"})
+	if strings.Contains(buf.String(), `"level":"WARN"`) && strings.Contains(buf.String(), "SET `password`='[REDACTED]' WHERE `id` = 1") &&
+		!strings.Contains(buf.String(), "secret") {
+		t.Log(buf.String())
+	} else {
+		t.Error(buf.String())
+	}
+
+	buf.Reset()
+	fake.errs = []error{errors.New("failed")}
+	b.Where("id", 1).DeleteContext(ctx)
+	if strings.Contains(buf.String(), `"level":"ERROR"`) && strings.Contains(buf.String(), `"error":"failed"`) {
+		t.Log(buf.String())
+	} else {
+		t.Error(buf.String())
+	}
+}
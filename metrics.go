@@ -0,0 +1,163 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"expvar"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MetricQueries 执行语句数量的计数器
+	MetricQueries = "sql_queries_total"
+	// MetricDuration 执行耗时（秒）的直方图
+	MetricDuration = "sql_query_duration_seconds"
+)
+
+// MetricsSink 指标的存储，可以适配 Prometheus、expvar 等
+type MetricsSink interface {
+	// Inc 计数器加 1
+	Inc(name string, labels map[string]string)
+	// Observe 直方图记录一次观测值
+	Observe(name string, value float64, labels map[string]string)
+}
+
+// MetricsHook 记录每条执行的语句的数量和耗时，标签为 operation、table、dialect、status 和 fingerprint
+type MetricsHook struct {
+	NopHook
+	sink MetricsSink
+}
+
+// NewMetricsHook 创建指标钩子
+func NewMetricsHook(sink MetricsSink) *MetricsHook {
+	return &MetricsHook{sink: sink}
+}
+
+func (h *MetricsHook) AfterExec(ctx context.Context, e *QueryEvent, result sql.Result, err error, duration time.Duration) {
+	dialect := e.Dialect
+	if dialect == nil {
+		dialect = MySQL
+	}
+
+	labels := map[string]string{
+		"operation":   sqlOperation(e),
+		"table":       e.Table,
+		"dialect":     dialect.Name,
+		"status":      ClassifyError(dialect, err),
+		"fingerprint": e.Dialect.Fingerprint(e.Sql),
+	}
+
+	h.sink.Inc(MetricQueries, labels)
+	h.sink.Observe(MetricDuration, duration.Seconds(), labels)
+}
+
+// ClassifyError 将执行错误归类为数量有限的状态：
+// ok、canceled、timeout、no_rows、stale、connection、retryable（死锁等）、constraint（违反约束）、error
+func ClassifyError(dialect *Dialect, err error) string {
+	if dialect == nil {
+		dialect = MySQL
+	}
+
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, sql.ErrNoRows):
+		return "no_rows"
+	case errors.Is(err, ErrStaleRecord):
+		return "stale"
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		return "connection"
+	case IsRetryable(dialect, err):
+		return "retryable"
+	}
+
+	for _, code := range errorCodes(err) {
+		if slices.Contains(dialect.constraintCodes, code) {
+			return "constraint"
+		}
+	}
+
+	return "error"
+}
+
+// metricKey 返回 name{k="v",...} 形式的指标名，标签按名称排序
+func metricKey(name string, labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, k+"="+strconv.Quote(labels[k]))
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// MemorySink 在内存中记录指标，用于测试
+type MemorySink struct {
+	mu           sync.Mutex
+	counters     map[string]int
+	observations map[string][]float64
+}
+
+// NewMemorySink 创建内存指标存储
+func NewMemorySink() *MemorySink {
+	return &MemorySink{counters: make(map[string]int), observations: make(map[string][]float64)}
+}
+
+func (s *MemorySink) Inc(name string, labels map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counters[metricKey(name, labels)]++
+}
+
+func (s *MemorySink) Observe(name string, value float64, labels map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := metricKey(name, labels)
+	s.observations[key] = append(s.observations[key], value)
+}
+
+// Counter 返回计数器的值
+func (s *MemorySink) Counter(name string, labels map[string]string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.counters[metricKey(name, labels)]
+}
+
+// Observations 返回直方图的所有观测值
+func (s *MemorySink) Observations(name string, labels map[string]string) []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.observations[metricKey(name, labels)])
+}
+
+// ExpvarSink 通过 expvar 发布指标，直方图记录为 _count 和 _sum
+type ExpvarSink struct {
+	m *expvar.Map
+}
+
+// NewExpvarSink 创建 expvar 指标存储，name 为发布的变量名，同名变量只能创建一次
+func NewExpvarSink(name string) *ExpvarSink {
+	return &ExpvarSink{m: expvar.NewMap(name)}
+}
+
+func (s *ExpvarSink) Inc(name string, labels map[string]string) {
+	s.m.Add(metricKey(name, labels), 1)
+}
+
+func (s *ExpvarSink) Observe(name string, value float64, labels map[string]string) {
+	s.m.Add(metricKey(name+"_count", labels), 1)
+	s.m.AddFloat(metricKey(name+"_sum", labels), value)
+}
@@ -0,0 +1,81 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		dialect *Dialect
+		err     error
+		want    string
+	}{
+		{MySQL, nil, "ok"},
+		{MySQL, context.Canceled, "canceled"},
+		{MySQL, fmt.Errorf("query: %w", context.DeadlineExceeded), "timeout"},
+		{MySQL, sql.ErrNoRows, "no_rows"},
+		{MySQL, ErrStaleRecord, "stale"},
+		{MySQL, &mysqlError{Number: 1213}, "retryable"},
+		{MySQL, &mysqlError{Number: 1062}, "constraint"},
+		{Postgres, &pgError{Code: "23505"}, "constraint"},
+		{Postgres, &mysqlError{Number: 1062}, "error"},
+		{MySQL, errors.New("failed"), "error"},
+	}
+
+	for _, test := range tests {
+		if got := ClassifyError(test.dialect, test.err); got != test.want {
+			t.Error(test.err, got)
+		}
+	}
+}
+
+func TestMetricsHook(t *testing.T) {
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	sink := NewMemorySink()
+	b := NewBuilder("user").SetExecutor(db).Hook(NewMetricsHook(sink))
+
+	b.Where("id", "IN", []int{1, 2}).DeleteContext(ctx)
+	b.Where("id", "IN", []int{1, 2}).DeleteContext(ctx)
+	fake.errs = []error{&mysqlError{Number: 1213}}
+	b.Where("id", "IN", []int{1, 2}).DeleteContext(ctx)
+
+	labels := map[string]string{
+		"operation":   "DELETE",
+		"table":       "user",
+		"dialect":     "mysql",
+		"status":      "ok",
+		"fingerprint": MySQL.Fingerprint("delete from `user` WHERE `id` IN (?,?)"),
+	}
+	if n := sink.Counter(MetricQueries, labels); n != 2 {
+		t.Error(n)
+	}
+	if observations := sink.Observations(MetricDuration, labels); len(observations) != 2 {
+		t.Error(observations)
+	}
+
+	labels["status"] = "retryable"
+	if n := sink.Counter(MetricQueries, labels); n != 1 {
+		t.Error(n)
+	}
+}
+
+func TestExpvarSink(t *testing.T) {
+	sink := NewExpvarSink("sqlbuilder_test")
+	labels := map[string]string{"table": "user", "operation": "SELECT"}
+	sink.Inc(MetricQueries, labels)
+	sink.Inc(MetricQueries, labels)
+	sink.Observe(MetricDuration, 0.5, labels)
+
+	if v := sink.m.Get(`sql_queries_total{operation="SELECT",table="user"}`); v == nil || v.String() != "2" {
+		t.Error(v)
+	}
+	if v := sink.m.Get(`sql_query_duration_seconds_sum{operation="SELECT",table="user"}`); v == nil || v.String() != "0.5" {
+		t.Error(v)
+	}
+}
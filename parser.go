@@ -0,0 +1,487 @@
+package sqlBuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind uint8
+
+const (
+	tokenIdent tokenKind = iota
+	tokenQuotedIdent
+	tokenKeyword
+	tokenString
+	tokenNumber
+	tokenPunct
+	tokenOperator
+	tokenComment
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	space bool // 前面是否有空白
+	quote byte // 带引号标识符的开始引号
+}
+
+// keywords 字段表达式中不作为标识符引用的关键字
+var keywords = map[string]bool{
+	"AS": true, "DISTINCT": true, "ALL": true, "AND": true, "OR": true, "XOR": true, "NOT": true,
+	"NULL": true, "IS": true, "IN": true, "LIKE": true, "REGEXP": true, "BETWEEN": true,
+	"CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true, "TRUE": true, "FALSE": true,
+	"INTERVAL": true, "DIV": true, "MOD": true, "ASC": true, "DESC": true, "SEPARATOR": true,
+	"OVER": true, "PARTITION": true, "ORDER": true, "BY": true,
+	// 子查询中的子句关键字
+	"SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "HAVING": true, "LIMIT": true, "OFFSET": true,
+	"UNION": true, "JOIN": true, "INNER": true, "OUTER": true, "CROSS": true, "ON": true, "USING": true,
+	"EXISTS": true, "ANY": true, "SOME": true,
+}
+
+// niladicFunctions 不带括号调用的函数
+var niladicFunctions = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "CURRENT_USER": true,
+	"LOCALTIME": true, "LOCALTIMESTAMP": true, "SESSION_USER": true, "UTC_DATE": true, "UTC_TIME": true, "UTC_TIMESTAMP": true,
+}
+
+// intervalUnits INTERVAL 表达式的时间单位
+var intervalUnits = map[string]bool{
+	"MICROSECOND": true, "SECOND": true, "MINUTE": true, "HOUR": true, "DAY": true, "WEEK": true, "MONTH": true,
+	"QUARTER": true, "YEAR": true, "SECOND_MICROSECOND": true, "MINUTE_MICROSECOND": true, "MINUTE_SECOND": true,
+	"HOUR_MICROSECOND": true, "HOUR_SECOND": true, "HOUR_MINUTE": true, "DAY_MICROSECOND": true, "DAY_SECOND": true,
+	"DAY_MINUTE": true, "DAY_HOUR": true, "YEAR_MONTH": true,
+}
+
+// joinWords 后面跟着 JOIN 时作为连接类型的单词，其他位置可以作为函数名或字段名
+var joinWords = map[string]bool{"LEFT": true, "RIGHT": true, "FULL": true, "NATURAL": true}
+
+const operatorChars = "+-*/%=<>!|&^~?#:"
+
+// identQuote 返回标识符引号 c 对应的结束引号，c 不是标识符引号时返回 0
+func identQuote(c byte, d *Dialect) byte {
+	switch {
+	case c == '`':
+		return '`'
+	case c == d.quoteOpen[0]:
+		return d.quoteClose[0]
+	}
+	return 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isSpecialChar(c byte) bool {
+	return c == '(' || c == ')' || c == ',' || c == '.' || c == '\'' || c == '"' || c == '`' ||
+		strings.IndexByte(operatorChars, c) >= 0 || isSpace(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// numberEnd 返回数字字面量的结束位置，支持小数、1e3 形式的指数和 0x 开头的十六进制
+func numberEnd(s string, i int) int {
+	if strings.HasPrefix(s[i:], "0x") || strings.HasPrefix(s[i:], "0X") {
+		end := i + 2
+		for end < len(s) && isHexDigit(s[end]) {
+			end++
+		}
+		if end > i+2 {
+			return end
+		}
+	}
+
+	for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+		i++
+	}
+
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		exp := i + 1
+		if exp < len(s) && (s[exp] == '+' || s[exp] == '-') {
+			exp++
+		}
+		if exp < len(s) && isDigit(s[exp]) {
+			for i = exp; i < len(s) && isDigit(s[i]); i++ {
+			}
+		}
+	}
+
+	return i
+}
+
+// readQuoted 读取引号包裹的内容，双写的结束引号视为转义，返回去掉引号后的内容和结束位置
+func readQuoted(s string, i int, closeQuote byte) (string, int) {
+	var text strings.Builder
+	for i++; i < len(s); i++ {
+		if s[i] == closeQuote {
+			if i+1 < len(s) && s[i+1] == closeQuote {
+				text.WriteByte(closeQuote)
+				i++
+				continue
+			}
+			return text.String(), i + 1
+		}
+		text.WriteByte(s[i])
+	}
+	return text.String(), i
+}
+
+// readString 读取字符串字面量，返回结束位置，MySQL 中反斜杠转义下一个字符
+func readString(s string, i int, quote byte, d *Dialect) int {
+	if d.Name != MySQL.Name {
+		_, end := readQuoted(s, i, quote)
+		return end
+	}
+
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// commentEnd 判断 s[i:] 是否为注释，返回注释的结束位置，不是注释时返回 0。
+// 单行注释不包含结尾的换行，MySQL 中 -- 后面需要有空白，# 也是单行注释
+func commentEnd(s string, i int, d *Dialect) int {
+	rest := s[i:]
+	switch {
+	case strings.HasPrefix(rest, "/*"):
+		if end := strings.Index(rest[2:], "*/"); end >= 0 {
+			return i + 2 + end + 2
+		}
+		return len(s)
+	case strings.HasPrefix(rest, "--"):
+		if d.Name == MySQL.Name && len(rest) > 2 && !isSpace(rest[2]) {
+			return 0
+		}
+	case rest[0] == '#':
+		if d.Name != MySQL.Name {
+			return 0
+		}
+	default:
+		return 0
+	}
+
+	if end := strings.IndexByte(rest, '\n'); end >= 0 {
+		return i + end
+	}
+	return len(s)
+}
+
+// isLineComment 判断注释是否为单行注释
+func (tok token) isLineComment() bool {
+	return tok.kind == tokenComment && !strings.HasPrefix(tok.text, "/*")
+}
+
+// tokenize 将字段表达式切分为词法单元
+func tokenize(s string, d *Dialect) []token {
+	tokens := make([]token, 0, 8)
+	space := false
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		if isSpace(c) {
+			space = true
+			i++
+			continue
+		}
+
+		start := i
+		tok := token{space: space}
+		switch {
+		case identQuote(c, d) != 0:
+			tok.kind, tok.quote = tokenQuotedIdent, c
+			tok.text, i = readQuoted(s, i, identQuote(c, d))
+		case c == '\'' || c == '"':
+			i = readString(s, i, c, d)
+			tok.kind, tok.text = tokenString, s[start:i]
+		case commentEnd(s, i, d) > 0:
+			i = commentEnd(s, i, d)
+			tok.kind, tok.text = tokenComment, s[start:i]
+		case c == '(' || c == ')' || c == ',' || c == '.':
+			tok.kind, tok.text = tokenPunct, string(c)
+			i++
+		case strings.IndexByte(operatorChars, c) >= 0:
+			for i < len(s) && strings.IndexByte(operatorChars, s[i]) >= 0 && s[i] != '?' {
+				i++
+			}
+			if i == start {
+				i++
+			}
+			tok.kind, tok.text = tokenOperator, s[start:i]
+		case isDigit(c):
+			i = numberEnd(s, i)
+			tok.kind, tok.text = tokenNumber, s[start:i]
+		default:
+			for i < len(s) && !isSpecialChar(s[i]) {
+				i++
+			}
+			tok.kind, tok.text = tokenIdent, s[start:i]
+			if keywords[strings.ToUpper(tok.text)] {
+				tok.kind = tokenKeyword
+			}
+		}
+
+		tokens = append(tokens, tok)
+		space = false
+	}
+
+	return tokens
+}
+
+// quoted 返回带引号标识符的原始写法
+func (tok token) quoted(d *Dialect) string {
+	closeQuote := string(identQuote(tok.quote, d))
+	return string(tok.quote) + strings.ReplaceAll(tok.text, closeQuote, closeQuote+closeQuote) + closeQuote
+}
+
+// splitFields 按顶层逗号切分字段列表，忽略括号和引号内的逗号
+func splitFields(s string, d *Dialect) []string {
+	fields := make([]string, 0, 4)
+	depth, start := 0, 0
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case identQuote(c, d) != 0:
+			_, i = readQuoted(s, i, identQuote(c, d))
+			continue
+		case c == '\'' || c == '"':
+			i = readString(s, i, c, d)
+			continue
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+		i++
+	}
+
+	return append(fields, s[start:])
+}
+
+// isExprEnd 判断词法单元能否作为一个表达式的结尾
+func isExprEnd(tok token) bool {
+	switch tok.kind {
+	case tokenIdent, tokenQuotedIdent, tokenString, tokenNumber:
+		return true
+	case tokenPunct:
+		return tok.text == ")"
+	case tokenOperator:
+		return tok.text == "*"
+	case tokenKeyword:
+		upper := strings.ToUpper(tok.text)
+		return upper == "END" || upper == "NULL" || upper == "TRUE" || upper == "FALSE"
+	}
+	return false
+}
+
+// isQualifiedPart 判断点号后的词法单元能否作为限定名的一段，关键字在点号后也视为标识符
+func isQualifiedPart(tok token) bool {
+	return tok.kind == tokenIdent || tok.kind == tokenQuotedIdent || tok.kind == tokenKeyword || tok.text == "*"
+}
+
+// splitAlias 拆分出字段表达式末尾的别名，支持 `expr as alias` 和 `expr alias`
+func splitAlias(tokens []token) ([]token, string) {
+	n := len(tokens)
+	if n < 2 {
+		return tokens, ""
+	}
+
+	last := tokens[n-1]
+	if last.kind != tokenIdent && last.kind != tokenQuotedIdent {
+		return tokens, ""
+	}
+
+	prev := tokens[n-2]
+	if prev.kind == tokenKeyword && strings.EqualFold(prev.text, "AS") {
+		return tokens[:n-2], last.text
+	}
+
+	if n > 2 && isIntervalUnit(tokens, n-1) {
+		return tokens, ""
+	}
+
+	// a * b 中的 * 是乘号，只有 t.* 和 count(*) 中的 * 才是表达式的结尾
+	if prev.text == "*" && n > 2 && tokens[n-3].text != "." && tokens[n-3].text != "(" {
+		return tokens, ""
+	}
+
+	if last.space && isExprEnd(prev) {
+		depth := 0
+		for _, tok := range tokens[:n-1] {
+			if tok.kind == tokenPunct && tok.text == "(" {
+				depth++
+			} else if tok.kind == tokenPunct && tok.text == ")" {
+				depth--
+			}
+		}
+		if depth == 0 {
+			return tokens[:n-1], last.text
+		}
+	}
+
+	return tokens, ""
+}
+
+// isIntervalUnit 判断 tokens[i] 是否为 INTERVAL n DAY 中的时间单位
+func isIntervalUnit(tokens []token, i int) bool {
+	return i > 1 && tokens[i].kind == tokenIdent && intervalUnits[strings.ToUpper(tokens[i].text)] &&
+		tokens[i-2].kind == tokenKeyword && strings.EqualFold(tokens[i-2].text, "INTERVAL")
+}
+
+// isBareWord 判断标识符是否原样输出：函数名、CAST(x AS CHAR) 和 x::text 中的类型名、
+// 不带括号的函数、INTERVAL 的时间单位以及 LEFT JOIN 等连接类型
+func isBareWord(tokens []token, i int) bool {
+	tok := tokens[i]
+	if tok.kind != tokenIdent {
+		return false
+	}
+
+	upper := strings.ToUpper(tok.text)
+	next := token{}
+	if i+1 < len(tokens) {
+		next = tokens[i+1]
+	}
+
+	switch {
+	case next.text == "(":
+		return true
+	case i > 0 && (tokens[i-1].kind == tokenKeyword && strings.EqualFold(tokens[i-1].text, "AS") || tokens[i-1].text == "::"):
+		return true
+	case niladicFunctions[upper]:
+		return next.text != "."
+	case joinWords[upper]:
+		return strings.EqualFold(next.text, "JOIN") || strings.EqualFold(next.text, "OUTER")
+	}
+	return isIntervalUnit(tokens, i)
+}
+
+// escapeExpr 解析单个字段表达式，只引用其中真正的标识符
+func (b *Builder) escapeExpr(field string) string {
+	tokens, alias := splitAlias(tokenize(field, b.GetDialect()))
+
+	sql := b.writeExpr(field, tokens)
+	if alias != "" {
+		sql += " as " + b.quote(alias)
+	}
+
+	return sql
+}
+
+// escapeColumn 解析条件、排序和分组中的字段，只允许标识符、限定名和函数调用，
+// 顶层出现运算符、关键字、注释或多个表达式时返回 ErrInvalidIdentifier，其他表达式需使用 Raw
+func (b *Builder) escapeColumn(field string) string {
+	tokens := tokenize(field, b.GetDialect())
+
+	depth := 0
+	for i, tok := range tokens {
+		switch {
+		case tok.kind == tokenPunct && tok.text == "(":
+			depth++
+			continue
+		case tok.kind == tokenPunct && tok.text == ")":
+			depth--
+		}
+
+		invalid := depth < 0 || tok.kind == tokenComment || tok.kind == tokenOperator && tok.text != "*" && tok.text != "::"
+		if depth == 0 && (i == 0 || tokens[i-1].text != ".") {
+			// 顶层的关键字和相邻的两个表达式，例如 id OR 1、`a` b
+			invalid = invalid || tok.kind == tokenKeyword ||
+				tok.kind != tokenPunct && tok.kind != tokenOperator && i > 0 && isExprEnd(tokens[i-1])
+		}
+		if invalid {
+			b.setErr(fmt.Errorf("%w: %q", ErrInvalidIdentifier, field))
+			return ""
+		}
+	}
+
+	if depth != 0 || len(tokens) == 0 {
+		b.setErr(fmt.Errorf("%w: %q", ErrInvalidIdentifier, field))
+		return ""
+	}
+
+	return b.writeExpr(field, tokens)
+}
+
+// writeExpr 输出表达式，引用其中的标识符，注释在任何模式下都返回错误
+func (b *Builder) writeExpr(field string, tokens []token) string {
+	var s strings.Builder
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.space && s.Len() > 0 {
+			s.WriteByte(' ')
+		}
+
+		switch tok.kind {
+		case tokenIdent, tokenQuotedIdent:
+			if isBareWord(tokens, i) {
+				b.checkId(tok.text)
+				s.WriteString(tok.text)
+				continue
+			}
+
+			// db.table.column 限定名，每一段分别引用
+			s.WriteString(b.quote(tok.text))
+			for i+2 < len(tokens) && tokens[i+1].text == "." && !tokens[i+1].space && !tokens[i+2].space &&
+				isQualifiedPart(tokens[i+2]) {
+				s.WriteString("." + b.quote(tokens[i+2].text))
+				i += 2
+			}
+		case tokenComment:
+			// 注释会吞掉后面的 FROM、WHERE 和全局作用域条件
+			b.setErr(fmt.Errorf("%w: %q", ErrInvalidIdentifier, field))
+		default:
+			// 严格模式下只允许标识符、函数调用、类型转换和 *，其他表达式需使用 Raw
+			if b.strict && tok.kind != tokenPunct && tok.text != "*" && tok.text != "::" {
+				b.setErr(fmt.Errorf("%w: %q", ErrInvalidIdentifier, field))
+			}
+			s.WriteString(tok.text)
+		}
+	}
+
+	return s.String()
+}
+
+// quoteName 引用 schema.table 形式的限定名，每一段分别引用
+func (b *Builder) quoteName(name string) string {
+	d := b.GetDialect()
+	parts := make([]string, 0, 2)
+
+	var part strings.Builder
+	for i := 0; i < len(name); {
+		c := name[i]
+		switch {
+		case identQuote(c, d) != 0:
+			var text string
+			text, i = readQuoted(name, i, identQuote(c, d))
+			part.WriteString(text)
+			continue
+		case c == '.':
+			parts = append(parts, b.quote(part.String()))
+			part.Reset()
+		default:
+			part.WriteByte(c)
+		}
+		i++
+	}
+
+	return strings.Join(append(parts, b.quote(part.String())), ".")
+}
@@ -0,0 +1,173 @@
+package sqlBuilder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_Select_Expression(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").Select("CONCAT(first, ' ', last) as name, COALESCE(nick,name) n").ToSql()
+	if sql == "SELECT CONCAT(`first`, ' ', `last`) as `name`,COALESCE(`nick`,`name`) as `n` FROM `user`" &&
+		reflect.DeepEqual(params, []interface{}{}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").Select("count(DISTINCT user_id) users", "price * qty AS total", "IFNULL(MAX(o.id), 0) max_id").ToSql()
+	if sql == "SELECT count(DISTINCT `user_id`) as `users`,`price` * `qty` as `total`,IFNULL(MAX(`o`.`id`), 0) as `max_id` FROM `user`" &&
+		reflect.DeepEqual(params, []interface{}{}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").Select("db.user.id", "u.*", "CASE WHEN age > 18 THEN 'adult' ELSE 'child' END stage").ToSql()
+	if sql == "SELECT `db`.`user`.`id`,`u`.*,CASE WHEN `age` > 18 THEN 'adult' ELSE 'child' END as `stage` FROM `user`" &&
+		reflect.DeepEqual(params, []interface{}{}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").Select("CAST(id AS CHAR) sid", "`order`", "t.desc").ToSql()
+	if sql == "SELECT CAST(`id` AS CHAR) as `sid`,`order`,`t`.`desc` FROM `user`" &&
+		reflect.DeepEqual(params, []interface{}{}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestSplitFields(t *testing.T) {
+	fields := splitFields("id, CONCAT(a, ',', b) c,`x,y`", MySQL)
+	if reflect.DeepEqual(fields, []string{"id", " CONCAT(a, ',', b) c", "`x,y`"}) {
+		t.Log(fields)
+	} else {
+		t.Error(fields)
+	}
+}
+
+func TestBuilder_IdentifierInjection(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").Select("age").Where("DATE(created_at)", "2024-01-01").Group("age,sex").
+		Having("count(*)", ">", 1).Order("count(*)").Order("age", "ASC; DROP TABLE user").ToSql()
+	if sql == "SELECT `age` FROM `user` WHERE DATE(`created_at`) = ? GROUP BY `age`,`sex` HAVING count(*) > ? ORDER BY count(*) DESC" &&
+		reflect.DeepEqual(params, []interface{}{"2024-01-01", 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").SetDialect(Postgres).Where("data::text", "x").Order("u.id", "asc").ToSql()
+	if sql == `SELECT * FROM "user" WHERE "data"::text = ? ORDER BY "u"."id" ASC` &&
+		reflect.DeepEqual(params, []interface{}{"x"}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	// 非严格模式下同样拒绝
+	for _, fn := range []func(b *Builder) *Builder{
+		func(b *Builder) *Builder { return b.Where("id OR 1=1 OR id", 5) },
+		func(b *Builder) *Builder { return b.Where("a` OR 1=1 -- ", 1) },
+		func(b *Builder) *Builder { return b.Where("a) OR (b", 1) },
+		func(b *Builder) *Builder { return b.Having("count(*) > 0 OR count(*)", 1) },
+		func(b *Builder) *Builder { return b.Order("id; DROP TABLE user") },
+		func(b *Builder) *Builder { return b.Group("age -- ") },
+		func(b *Builder) *Builder { return b.Select("a -- x") },
+		func(b *Builder) *Builder { return b.Select("a # x") },
+		func(b *Builder) *Builder { return b.Select("a /* x */") },
+	} {
+		b := NewBuilder("user")
+		sql, params = fn(b).ToSql()
+		if sql == "" && params == nil && errors.Is(b.Err(), ErrInvalidIdentifier) {
+			t.Log(b.Err())
+		} else {
+			t.Error(sql, params, b.Err())
+		}
+	}
+
+	for _, fn := range []func(b *Builder) *Builder{
+		func(b *Builder) *Builder { return b.Select("id -- ") },
+		func(b *Builder) *Builder { return b.Select("id, 1 OR 1") },
+		func(b *Builder) *Builder { return b.Select("'x' name") },
+	} {
+		b := NewBuilder("user").Strict(true)
+		sql, params = fn(b).ToSql()
+		if sql == "" && params == nil && errors.Is(b.Err(), ErrInvalidIdentifier) {
+			t.Log(b.Err())
+		} else {
+			t.Error(sql, params, b.Err())
+		}
+	}
+}
+
+func TestBuilder_Select_Tokens(t *testing.T) {
+	tests := []struct {
+		dialect *Dialect
+		field   string
+		want    string
+	}{
+		{MySQL, "DATE_ADD(d, INTERVAL 1 DAY) due", "DATE_ADD(`d`, INTERVAL 1 DAY) as `due`"},
+		{MySQL, "d + INTERVAL n HOUR_MINUTE", "`d` + INTERVAL `n` HOUR_MINUTE"},
+		{MySQL, "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP"},
+		{MySQL, "current_date today", "current_date as `today`"},
+		{MySQL, "1e3 * price", "1e3 * `price`"},
+		{MySQL, "1.5E-3 rate", "1.5E-3 as `rate`"},
+		{MySQL, "0xFF & flags", "0xFF & `flags`"},
+		{Postgres, "a::text", `"a"::text`},
+		{Postgres, "a::varchar(10) v", `"a"::varchar(10) as "v"`},
+		{MySQL, "(SELECT MAX(id) FROM x) m", "(SELECT MAX(`id`) FROM `x`) as `m`"},
+		{MySQL, "(SELECT COUNT(*) FROM post p LEFT JOIN tag t ON t.id = p.tag_id WHERE p.uid = u.id) n",
+			"(SELECT COUNT(*) FROM `post` `p` LEFT JOIN `tag` `t` ON `t`.`id` = `p`.`tag_id` WHERE `p`.`uid` = `u`.`id`) as `n`"},
+		{MySQL, "LEFT(name, 3)", "LEFT(`name`, 3)"},
+		{MySQL, "left", "`left`"},
+	}
+
+	for _, tt := range tests {
+		sql, _ := NewBuilder("user").SetDialect(tt.dialect).Select(tt.field).ToSql()
+		want := "SELECT " + tt.want + " FROM " + tt.dialect.Quote("user")
+		if sql != want {
+			t.Errorf("%s: got %s, want %s", tt.field, sql, want)
+		}
+	}
+}
+
+func TestTokenize_Comments(t *testing.T) {
+	tests := []struct {
+		sql  string
+		d    *Dialect
+		want []string
+	}{
+		{"a -- x\nb", MySQL, []string{"a", "-- x", "b"}},
+		{"a--1", MySQL, []string{"a", "--", "1"}},
+		{"a--1", Postgres, []string{"a", "--1"}},
+		{"a # x\nb", MySQL, []string{"a", "# x", "b"}},
+		{"a /* x\ny */ b", SQLite, []string{"a", "/* x\ny */", "b"}},
+		{`'a\'b' c`, MySQL, []string{`'a\'b'`, "c"}},
+		{`'a\' c`, Postgres, []string{`'a\'`, "c"}},
+	}
+
+	for _, test := range tests {
+		texts := make([]string, 0)
+		for _, tok := range tokenize(test.sql, test.d) {
+			texts = append(texts, tok.text)
+		}
+		if reflect.DeepEqual(texts, test.want) {
+			t.Log(texts)
+		} else {
+			t.Error(test.sql, texts)
+		}
+	}
+}
@@ -11,7 +11,7 @@ func (b *Builder) Select(args ...interface{}) *Builder {
 	if len(args) == 1 {
 		fieldArr := make([]string, 0)
 		if field, ok := args[0].(string); ok {
-			fieldArr = strings.Split(field, ",")
+			fieldArr = splitFields(field, b.GetDialect())
 		} else if field, ok := args[0].(Raw); ok {
 			b.methods.field = append(b.methods.field, field)
 			return b
@@ -30,6 +30,25 @@ func (b *Builder) Select(args ...interface{}) *Builder {
 	return b
 }
 
+// With 添加公用表表达式（CTE），query 支持 func(*Builder)、func() *Builder 和 *Builder
+func (b *Builder) With(name string, query interface{}) *Builder {
+	b.initialize()
+
+	sql, params := b.subQuery(query)
+	b.methods.with = append(b.methods.with, fmt.Sprintf("%s AS (%s)", b.quote(name), sql))
+	b.params["with"] = append(b.params["with"], params...)
+
+	return b
+}
+
+func (b *Builder) builderWith(sql string) (string, []interface{}) {
+	if len(b.methods.with) == 0 {
+		return sql, nil
+	}
+
+	return "WITH " + strings.Join(b.methods.with, ",") + " " + sql, b.params["with"]
+}
+
 func (b *Builder) Table(table interface{}) *Builder {
 	b.initialize()
 	b.tmpTableClosureCount, b.tmpTable, b.params["table"], b.TableAlias = b.setTable(table)
@@ -92,11 +111,12 @@ func (b *Builder) OrHaving(args ...interface{}) *Builder {
 
 func (b *Builder) Order(args ...interface{}) *Builder {
 	var (
-		field string
 		value string
+		ok    bool
 	)
-	field, ok := args[0].(string)
-	if !ok {
+	switch args[0].(type) {
+	case string, Raw:
+	default:
 		return b
 	}
 
@@ -107,8 +127,11 @@ func (b *Builder) Order(args ...interface{}) *Builder {
 	}
 
 	value = strings.ToUpper(value)
+	if value != "ASC" && value != "DESC" {
+		return b
+	}
 
-	b.methods.order = append(b.methods.order, b.escapeId(field)+" "+value)
+	b.methods.order = append(b.methods.order, b.column(args[0])+" "+value)
 
 	return b
 }
@@ -122,10 +145,8 @@ func (b *Builder) Order(args ...interface{}) *Builder {
 func (b *Builder) Limit(args ...int64) *Builder {
 
 	switch len(args) {
-	case 1:
-		b.methods.limit = fmt.Sprintf(" LIMIT %d", args[0])
-	case 2:
-		b.methods.limit = fmt.Sprintf(" LIMIT %d,%d", args[0], args[1])
+	case 1, 2:
+		b.methods.limit = append([]int64(nil), args...)
 	}
 
 	return b
@@ -136,12 +157,77 @@ func (b *Builder) Limit(args ...int64) *Builder {
 // param int64 listRows 每页数量
 // return *Builder
 func (b *Builder) Page(page int64, listRows int64) *Builder {
-	b.methods.limit = fmt.Sprintf(" LIMIT %d,%d", (page-1)*listRows, listRows)
+	b.methods.limit = []int64{(page - 1) * listRows, listRows}
 	return b
 }
 
+// limitSql 按方言生成数量限制，MySQL 为 LIMIT offset,length，SQLServer 为 OFFSET ... FETCH，其他为 LIMIT length OFFSET offset
+func (b *Builder) limitSql() string {
+	limit := b.methods.limit
+	if len(limit) == 0 {
+		return ""
+	}
+
+	offset, length := int64(0), limit[len(limit)-1]
+	if len(limit) == 2 {
+		offset = limit[0]
+	}
+
+	switch b.GetDialect().Name {
+	case MySQL.Name:
+		if len(limit) == 2 {
+			return fmt.Sprintf(" LIMIT %d,%d", offset, length)
+		}
+		return fmt.Sprintf(" LIMIT %d", length)
+	case SQLServer.Name:
+		return fmt.Sprintf(" OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, length)
+	}
+
+	if len(limit) == 2 {
+		return fmt.Sprintf(" LIMIT %d OFFSET %d", length, offset)
+	}
+	return fmt.Sprintf(" LIMIT %d", length)
+}
+
+// builderLimit 查询语句的数量限制，SQLServer 的 OFFSET ... FETCH 必须有排序，没有排序时按 (SELECT NULL) 排序
+func (b *Builder) builderLimit(sql string) string {
+	if len(b.methods.limit) == 0 {
+		return sql
+	}
+
+	if b.GetDialect().Name == SQLServer.Name && len(b.methods.order) == 0 {
+		sql += " ORDER BY (SELECT NULL)"
+	}
+
+	return sql + b.limitSql()
+}
+
+// builderMutationLimit 更新和删除语句的数量限制，只有 MySQL 支持
+func (b *Builder) builderMutationLimit(sql string) string {
+	if len(b.methods.limit) == 0 {
+		return sql
+	}
+
+	if d := b.GetDialect(); d.Name != MySQL.Name {
+		b.setErr(fmt.Errorf("%w: %s", ErrLimitUnsupported, d.Name))
+		return sql
+	}
+
+	return sql + b.limitSql()
+}
+
 func (b *Builder) ToSql() (string, []interface{}) {
+	return b.build("SELECT", b.selectSql)
+}
+
+// subQuerySql 构造作为子查询的SQL，不执行钩子
+func (b *Builder) subQuerySql() (string, []interface{}) {
 	defer b.cleanLastSql()
+	return b.result(b.selectSql())
+}
+
+func (b *Builder) selectSql() (string, []interface{}) {
+	b.applyGlobalScopes()
 
 	params := make([]interface{}, 0)
 
@@ -152,7 +238,8 @@ func (b *Builder) ToSql() (string, []interface{}) {
 		fieldStr = b.escapeId(b.methods.field)
 	}
 
-	sql := fmt.Sprintf("SELECT %s FROM %s", fieldStr, b.GetTable())
+	sql, withParams := b.builderWith(fmt.Sprintf("SELECT %s FROM %s", fieldStr, b.GetTable()))
+	params = append(params, withParams...)
 
 	if tableParams, ok := b.params["table"]; ok {
 		params = append(params, tableParams...)
@@ -170,17 +257,20 @@ func (b *Builder) ToSql() (string, []interface{}) {
 	params = append(params, whereParams...)
 
 	if len(b.methods.group) > 0 {
-		sql += " GROUP BY " + b.escapeId(b.methods.group)
+		group := make([]string, 0, len(b.methods.group))
+		for _, fields := range b.methods.group {
+			for _, field := range splitFields(fields, b.GetDialect()) {
+				group = append(group, b.column(strings.TrimSpace(field)))
+			}
+		}
+		sql += " GROUP BY " + strings.Join(group, ",")
 	}
 
 	sql, havingParams := b.builderHaving(sql)
 	params = append(params, havingParams...)
 
 	sql = b.builderOrder(sql)
-
-	if b.methods.limit != "" {
-		sql += b.methods.limit
-	}
+	sql = b.builderLimit(sql)
 
 	return sql, params
 }
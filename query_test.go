@@ -1,6 +1,7 @@
 package sqlBuilder
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -246,3 +247,84 @@ func TestBuilder_Page(t *testing.T) {
 		t.Error(sql, params)
 	}
 }
+
+func TestBuilder_Limit_Dialect(t *testing.T) {
+	tests := []struct {
+		dialect *Dialect
+		build   func(b *Builder) *Builder
+		want    string
+	}{
+		{Postgres, func(b *Builder) *Builder { return b.Limit(10) }, `SELECT "id" FROM "user" LIMIT 10`},
+		{Postgres, func(b *Builder) *Builder { return b.Limit(20, 10) }, `SELECT "id" FROM "user" LIMIT 10 OFFSET 20`},
+		{SQLite, func(b *Builder) *Builder { return b.Page(3, 10) }, `SELECT "id" FROM "user" LIMIT 10 OFFSET 20`},
+		{SQLServer, func(b *Builder) *Builder { return b.Limit(10) },
+			"SELECT [id] FROM [user] ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{SQLServer, func(b *Builder) *Builder { return b.Order("id", "asc").Page(2, 10) },
+			"SELECT [id] FROM [user] ORDER BY [id] ASC OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY"},
+	}
+
+	for _, tt := range tests {
+		sql, _ := tt.build(NewBuilder("user").SetDialect(tt.dialect).Select("id")).ToSql()
+		if sql != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.dialect.Name, sql, tt.want)
+		}
+	}
+
+	// 只有 MySQL 的更新和删除语句支持 LIMIT
+	b := NewBuilder("user").SetDialect(Postgres)
+	if sql, params := b.Where("id", ">", 1).Limit(10).Delete(); sql != "" || params != nil || !errors.Is(b.Err(), ErrLimitUnsupported) {
+		t.Error(sql, params, b.Err())
+	}
+
+	sql, _ := NewBuilder("user").Where("id", ">", 1).Limit(10).Delete()
+	if sql != "delete from `user` WHERE `id` > ? LIMIT 10" {
+		t.Error(sql)
+	}
+}
+
+func TestBuilder_Table_Schema(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").Table("analytics.events e").
+		Select("e.*", "analytics.events.user_id").
+		Where("analytics.events.type", 1).
+		Group("analytics.events.user_id").
+		ToSql()
+	if sql == "SELECT `e`.*,`analytics`.`events`.`user_id` FROM `analytics`.`events` as `e` WHERE `analytics`.`events`.`type` = ? GROUP BY `analytics`.`events`.`user_id`" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("analytics.events").SetDialect(Postgres).Where("id", 1).Delete()
+	if sql == `delete from "analytics"."events" WHERE "id" = ?` &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_With(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("recent").
+		With("recent", func(m *Builder) {
+			m.Table("order").Where("created_at", ">", "2024-01-01")
+		}).
+		Where("amount", ">", 100).
+		ToSql()
+	if sql == "WITH `recent` AS (SELECT * FROM `order` WHERE `created_at` > ?) SELECT * FROM `recent` WHERE `amount` > ?" &&
+		reflect.DeepEqual(params, []interface{}{"2024-01-01", 100}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
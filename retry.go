@@ -0,0 +1,150 @@
+package sqlBuilder
+
+import (
+	"context"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy 事务遇到死锁、序列化失败等可重试错误时的重试策略
+type RetryPolicy struct {
+	// MaxAttempts 最多执行次数（包括第一次），小于等于 1 时不重试
+	MaxAttempts int
+	// BaseDelay 第一次重试前的等待时间，之后每次翻倍
+	BaseDelay time.Duration
+	// MaxDelay 等待时间上限，0 表示不限制
+	MaxDelay time.Duration
+	// Retryable 判断错误是否可重试，为 nil 时使用 IsRetryable 按方言判断
+	Retryable func(dialect *Dialect, err error) bool
+	// OnRetry 每次重试前回调，attempt 为即将开始的执行次数
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// DefaultRetryPolicy 默认重试策略，最多执行 3 次
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second}
+
+// backoff 第 attempt 次重试前的等待时间，在 [delay/2, delay] 之间随机抖动
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func (p RetryPolicy) retryable(dialect *Dialect, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(dialect, err)
+	}
+	return IsRetryable(dialect, err)
+}
+
+// TransactionRetry 在事务中执行 fn，遇到可重试的错误时按 policy 回滚后重新执行整个事务
+func TransactionRetry(ctx context.Context, db DB, policy RetryPolicy, fn func(tx *Session) error) error {
+	return NewSession(db).Retry(policy).Transaction(ctx, fn)
+}
+
+// IsRetryable 判断错误是否为 dialect 下可以通过重新执行事务解决的错误：
+// MySQL 死锁（1213、40001）、Postgres 序列化失败（40001）和死锁（40P01）、SQLite 数据库繁忙（5、6）、SQLServer 死锁（1205）。
+// 通过驱动错误的 SQLState()、Number、Code 字段识别，不依赖具体驱动
+func IsRetryable(dialect *Dialect, err error) bool {
+	if err == nil {
+		return false
+	}
+	if dialect == nil {
+		dialect = MySQL
+	}
+
+	for _, code := range errorCodes(err) {
+		if slices.Contains(dialect.retryCodes, code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// errorCodes 提取错误链中驱动错误的错误码
+func errorCodes(err error) []string {
+	codes := make([]string, 0)
+
+	for _, e := range unwrapAll(err) {
+		if state, ok := e.(interface{ SQLState() string }); ok {
+			codes = append(codes, state.SQLState())
+		}
+
+		v := reflect.ValueOf(e)
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				break
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+
+		for _, name := range []string{"Number", "Code"} {
+			field := v.FieldByName(name)
+			if !field.IsValid() {
+				continue
+			}
+
+			switch field.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				codes = append(codes, strconv.FormatInt(field.Int(), 10))
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				codes = append(codes, strconv.FormatUint(field.Uint(), 10))
+			case reflect.String:
+				codes = append(codes, strings.ToUpper(field.String()))
+			}
+		}
+	}
+
+	return codes
+}
+
+func unwrapAll(err error) []error {
+	errs := []error{err}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := e.Unwrap(); inner != nil {
+			errs = append(errs, unwrapAll(inner)...)
+		}
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			errs = append(errs, unwrapAll(inner)...)
+		}
+	}
+
+	return errs
+}
+
+// sleep 等待 d，ctx 结束时提前返回
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
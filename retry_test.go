@@ -0,0 +1,123 @@
+package sqlBuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mysqlError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *mysqlError) Error() string {
+	return fmt.Sprintf("Error %d: %s", e.Number, e.Message)
+}
+
+type pgError struct {
+	Code string
+}
+
+func (e *pgError) Error() string {
+	return "ERROR (SQLSTATE " + e.Code + ")"
+}
+
+func (e *pgError) SQLState() string {
+	return e.Code
+}
+
+func TestIsRetryable(t *testing.T) {
+	deadlock := &mysqlError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+	serialization := &pgError{Code: "40001"}
+
+	tests := []struct {
+		dialect *Dialect
+		err     error
+		want    bool
+	}{
+		{MySQL, deadlock, true},
+		{MySQL, fmt.Errorf("debit: %w", deadlock), true},
+		{MySQL, &mysqlError{Number: 1062}, false},
+		{Postgres, serialization, true},
+		{Postgres, errors.Join(errors.New("commit"), &pgError{Code: "40P01"}), true},
+		{Postgres, &pgError{Code: "23505"}, false},
+		{Postgres, deadlock, false},
+		{nil, deadlock, true},
+		{MySQL, nil, false},
+	}
+
+	for _, test := range tests {
+		if got := IsRetryable(test.dialect, test.err); got != test.want {
+			t.Error(test.dialect, test.err, got)
+		}
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+	for attempt, max := range map[int]time.Duration{1: 10, 2: 20, 3: 40, 4: 50, 10: 50} {
+		max *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			if delay := policy.backoff(attempt); delay < max/2 || delay > max {
+				t.Error(attempt, delay)
+			}
+		}
+	}
+
+	if delay := (RetryPolicy{}).backoff(1); delay != 0 {
+		t.Error(delay)
+	}
+}
+
+func TestTransactionRetry(t *testing.T) {
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	deadlock := &mysqlError{Number: 1213}
+	fake.errs = []error{nil, deadlock}
+
+	retries := make([]int, 0)
+	policy := RetryPolicy{MaxAttempts: 3, OnRetry: func(attempt int, err error, delay time.Duration) {
+		retries = append(retries, attempt)
+	}}
+
+	err := TransactionRetry(ctx, db, policy, func(tx *Session) error {
+		_, err := tx.NewBuilder("wallet").Where("id", 1).UpdateContext(ctx, map[string]interface{}{"balance": Expr("`balance` - ?", 10)})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := fake.queries()
+	update := "UPDATE `wallet` SET `balance`=`balance` - ? WHERE `id` = ?"
+	if reflect.DeepEqual(queries, []string{"BEGIN", update, "ROLLBACK", "BEGIN", update, "COMMIT"}) && reflect.DeepEqual(retries, []int{2}) {
+		t.Log(queries)
+	} else {
+		t.Error(queries, retries)
+	}
+
+	attempts := 0
+	err = TransactionRetry(ctx, db, policy, func(tx *Session) error {
+		attempts++
+		return deadlock
+	})
+	if !errors.Is(err, deadlock) || attempts != 3 {
+		t.Error(err, attempts)
+	}
+
+	attempts = 0
+	errFailed := errors.New("failed")
+	err = TransactionRetry(ctx, db, policy, func(tx *Session) error {
+		attempts++
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) || attempts != 1 {
+		t.Error(err, attempts)
+	}
+}
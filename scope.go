@@ -0,0 +1,106 @@
+package sqlBuilder
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Scope 可复用的查询条件
+type Scope func(*Builder) *Builder
+
+type globalScope struct {
+	name  string
+	scope Scope
+}
+
+var (
+	globalScopesMu sync.RWMutex
+	globalScopes   = make(map[string][]globalScope)
+)
+
+// RegisterGlobalScope 为表注册全局作用域，ToSql、Update、Delete 时自动应用，同名作用域会被替换
+func RegisterGlobalScope(table string, name string, scope Scope) {
+	globalScopesMu.Lock()
+	defer globalScopesMu.Unlock()
+
+	for k, v := range globalScopes[table] {
+		if v.name == name {
+			globalScopes[table][k].scope = scope
+			return
+		}
+	}
+	globalScopes[table] = append(globalScopes[table], globalScope{name: name, scope: scope})
+}
+
+// RemoveGlobalScope 移除表的全局作用域
+func RemoveGlobalScope(table string, name string) {
+	globalScopesMu.Lock()
+	defer globalScopesMu.Unlock()
+
+	globalScopes[table] = slices.DeleteFunc(globalScopes[table], func(v globalScope) bool {
+		return v.name == name
+	})
+}
+
+// Scopes 依次应用作用域
+func (b *Builder) Scopes(scopes ...Scope) *Builder {
+	for _, scope := range scopes {
+		scope(b)
+	}
+	return b
+}
+
+// WithoutScope 本次查询不应用指定名称的全局作用域，不传名称时不应用任何全局作用域
+func (b *Builder) WithoutScope(names ...string) *Builder {
+	if len(names) == 0 {
+		b.methods.withoutScopes = []string{"*"}
+		return b
+	}
+	b.methods.withoutScopes = append(b.methods.withoutScopes, names...)
+	return b
+}
+
+// scopeTable 返回用于匹配全局作用域的表名
+func (b *Builder) scopeTable() string {
+	if b.tmpTable != "" {
+		if b.tmpTableClosureCount > 0 {
+			return ""
+		}
+		return b.tmpTable
+	}
+	return b.TableName
+}
+
+// applyGlobalScopes 应用当前表的全局作用域
+func (b *Builder) applyGlobalScopes() {
+	if slices.Contains(b.methods.withoutScopes, "*") {
+		return
+	}
+
+	globalScopesMu.RLock()
+	scopes := slices.Clone(globalScopes[b.scopeTable()])
+	globalScopesMu.RUnlock()
+
+	scopes = slices.DeleteFunc(scopes, func(v globalScope) bool {
+		return slices.Contains(b.methods.withoutScopes, v.name)
+	})
+	if len(scopes) == 0 {
+		return
+	}
+
+	b.groupWhere()
+	for _, v := range scopes {
+		v.scope(b)
+	}
+}
+
+// groupWhere 已有条件整体加括号，避免其中的 OR 与之后追加的条件的优先级混淆
+func (b *Builder) groupWhere() {
+	if len(b.methods.where) == 0 || b.methods.groupedWhere == len(b.methods.where) {
+		return
+	}
+	b.methods.where = []string{fmt.Sprintf(" (%s)", strings.Trim(strings.Join(b.methods.where, ""), " "))}
+	b.methods.groupedWhere = 1
+}
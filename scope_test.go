@@ -0,0 +1,94 @@
+package sqlBuilder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func active(b *Builder) *Builder {
+	return b.WhereNull("deleted_at")
+}
+
+func tenant(id int) Scope {
+	return func(b *Builder) *Builder {
+		return b.Where("tenant_id", id)
+	}
+}
+
+func TestBuilder_Scopes(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").Where("sex", 1).Scopes(active, tenant(7)).ToSql()
+	if sql == "SELECT * FROM `user` WHERE `sex` = ? AND `deleted_at` IS NULL AND `tenant_id` = ?" &&
+		reflect.DeepEqual(params, []interface{}{1, 7}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_GlobalScope(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	RegisterGlobalScope("scope_order", "tenant", tenant(7))
+	RegisterGlobalScope("scope_order", "active", active)
+	defer RemoveGlobalScope("scope_order", "tenant")
+	defer RemoveGlobalScope("scope_order", "active")
+
+	b := NewBuilder("scope_order")
+	sql, params = b.Where("id", 1).OrWhere("id", 2).ToSql()
+	if sql == "SELECT * FROM `scope_order` WHERE (`id` = ? OR `id` = ?) AND `tenant_id` = ? AND `deleted_at` IS NULL" &&
+		reflect.DeepEqual(params, []interface{}{1, 2, 7}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.WithoutScope("tenant").Where("id", 1).Update(map[string]interface{}{"name": "test"})
+	if sql == "UPDATE `scope_order` SET `name`=? WHERE (`id` = ?) AND `deleted_at` IS NULL" &&
+		reflect.DeepEqual(params, []interface{}{"test", 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Where(Raw("status = 1 OR status = 2")).ToSql()
+	if sql == "SELECT * FROM `scope_order` WHERE (status = 1 OR status = 2) AND `tenant_id` = ? AND `deleted_at` IS NULL" &&
+		reflect.DeepEqual(params, []interface{}{7}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Where(Raw("status = 1 OR status = 2")).Update(map[string]interface{}{"name": "test"})
+	if sql == "UPDATE `scope_order` SET `name`=? WHERE (status = 1 OR status = 2) AND `tenant_id` = ? AND `deleted_at` IS NULL" &&
+		reflect.DeepEqual(params, []interface{}{"test", 7}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.WithoutScope().Where("id", 1).Delete()
+	if sql == "delete from `scope_order` WHERE `id` = ?" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").WhereIn("order_id", func(m *Builder) {
+		m.Table("scope_order o").Select("id")
+	}).ToSql()
+	if sql == "SELECT * FROM `user` WHERE `order_id` IN (SELECT `id` FROM `scope_order` as `o` WHERE `tenant_id` = ? AND `deleted_at` IS NULL)" &&
+		reflect.DeepEqual(params, []interface{}{7}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
@@ -0,0 +1,167 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+)
+
+// DB 可以执行SQL并开启事务的数据库连接，例如 *sql.DB
+type DB interface {
+	Executor
+	TxBeginner
+}
+
+// Session 数据库会话，由会话创建的构造器自动使用会话的连接执行，事务中为事务连接
+type Session struct {
+	db       DB
+	executor Executor
+	dialect  *Dialect
+	retry    *RetryPolicy
+	hooks    []Hook
+	depth    int
+}
+
+// NewSession 创建数据库会话
+func NewSession(db DB) *Session {
+	return &Session{db: db, executor: db}
+}
+
+// SetDialect 指定会话创建的构造器使用的方言
+func (s *Session) SetDialect(dialect *Dialect) *Session {
+	s.dialect = dialect
+	return s
+}
+
+// Retry 指定事务的重试策略，只在最外层事务生效
+func (s *Session) Retry(policy RetryPolicy) *Session {
+	s.retry = &policy
+	return s
+}
+
+// Hook 为会话创建的构造器添加钩子
+func (s *Session) Hook(hooks ...Hook) *Session {
+	s.hooks = append(slices.Clip(s.hooks), hooks...)
+	return s
+}
+
+// NewBuilder 创建绑定会话连接的构造器
+func (s *Session) NewBuilder(tableName string) *Builder {
+	b := NewBuilder(tableName).SetExecutor(s.executor).Hook(s.hooks...)
+	b.dialect = s.dialect
+	return b
+}
+
+// InTransaction 会话是否处于事务中
+func (s *Session) InTransaction() bool {
+	return s.depth > 0
+}
+
+// Transaction 在事务中执行 fn，fn 返回 nil 时提交，返回错误或 panic 时回滚。
+// 在事务会话中再次调用时使用 SAVEPOINT 实现嵌套事务，只回滚到保存点
+func Transaction(ctx context.Context, db DB, fn func(tx *Session) error) error {
+	return NewSession(db).Transaction(ctx, fn)
+}
+
+// Transaction 在事务中执行 fn，会话已处于事务中时使用 SAVEPOINT。
+// 指定了重试策略时，遇到可重试的错误会回滚并重新执行整个事务
+func (s *Session) Transaction(ctx context.Context, fn func(tx *Session) error) error {
+	if s.InTransaction() {
+		return s.savepoint(ctx, fn)
+	}
+
+	if s.retry == nil {
+		return s.transaction(ctx, fn)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := s.transaction(ctx, fn)
+		if err == nil || attempt >= s.retry.MaxAttempts || !s.retry.retryable(s.dialect, err) {
+			return err
+		}
+
+		delay := s.retry.backoff(attempt)
+		if s.retry.OnRetry != nil {
+			s.retry.OnRetry(attempt+1, err, delay)
+		}
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+func (s *Session) transaction(ctx context.Context, fn func(tx *Session) error) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	txSession := &Session{db: s.db, executor: tx, dialect: s.dialect, hooks: s.hooks, depth: 1}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err = fn(txSession); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Session) savepoint(ctx context.Context, fn func(tx *Session) error) (err error) {
+	name := fmt.Sprintf("sp%d", s.depth)
+	create, rollback, release := s.savepointSql(name)
+
+	if _, err = s.executor.ExecContext(ctx, create); err != nil {
+		return err
+	}
+
+	txSession := &Session{db: s.db, executor: s.executor, dialect: s.dialect, hooks: s.hooks, depth: s.depth + 1}
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.executor.ExecContext(ctx, rollback)
+			panic(r)
+		}
+	}()
+
+	if err = fn(txSession); err != nil {
+		if _, rollbackErr := s.executor.ExecContext(ctx, rollback); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	if release != "" {
+		_, err = s.executor.ExecContext(ctx, release)
+	}
+	return err
+}
+
+func (s *Session) savepointSql(name string) (create string, rollback string, release string) {
+	dialect := s.dialect
+	if dialect == nil {
+		dialect = MySQL
+	}
+
+	name = dialect.Quote(name)
+	if dialect.Name == SQLServer.Name {
+		return "SAVE TRANSACTION " + name, "ROLLBACK TRANSACTION " + name, ""
+	}
+
+	return "SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name, "RELEASE SAVEPOINT " + name
+}
+
+// Tx 返回事务连接，会话不在事务中时返回 nil
+func (s *Session) Tx() *sql.Tx {
+	tx, _ := s.executor.(*sql.Tx)
+	return tx
+}
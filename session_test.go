@@ -0,0 +1,98 @@
+package sqlBuilder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	err := Transaction(ctx, db, func(tx *Session) error {
+		_, err := tx.NewBuilder("user").Where("id", 1).Where(func(b *Builder) {
+			b.Where("age", ">", 18)
+		}).UpdateContext(ctx, map[string]interface{}{"name": "a"})
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Transaction(ctx, func(tx *Session) error {
+			tx.NewBuilder("log").InsertContext(ctx, map[string]interface{}{"id": 1})
+			return errors.New("nested")
+		}); err == nil {
+			t.Error("nested error lost")
+		}
+
+		return tx.Transaction(ctx, func(tx *Session) error {
+			_, err := tx.NewBuilder("log").InsertContext(ctx, map[string]interface{}{"id": 2})
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := fake.queries()
+	if reflect.DeepEqual(queries, []string{
+		"BEGIN",
+		"UPDATE `user` SET `name`=? WHERE `id` = ? AND (`age` > ?)",
+		"SAVEPOINT `sp1`",
+		"INSERT INTO `log` (`id`) VALUES(?)",
+		"ROLLBACK TO SAVEPOINT `sp1`",
+		"SAVEPOINT `sp1`",
+		"INSERT INTO `log` (`id`) VALUES(?)",
+		"RELEASE SAVEPOINT `sp1`",
+		"COMMIT",
+	}) {
+		t.Log(queries)
+	} else {
+		t.Error(queries)
+	}
+}
+
+func TestTransaction_Rollback(t *testing.T) {
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	errFailed := errors.New("failed")
+	if err := Transaction(ctx, db, func(tx *Session) error {
+		return errFailed
+	}); !errors.Is(err, errFailed) {
+		t.Error(err)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Error(r)
+			}
+		}()
+		Transaction(ctx, db, func(tx *Session) error {
+			return tx.Transaction(ctx, func(tx *Session) error {
+				panic("boom")
+			})
+		})
+	}()
+
+	queries := fake.queries()
+	if reflect.DeepEqual(queries, []string{
+		"BEGIN", "ROLLBACK",
+		"BEGIN", "SAVEPOINT `sp1`", "ROLLBACK TO SAVEPOINT `sp1`", "ROLLBACK",
+	}) {
+		t.Log(queries)
+	} else {
+		t.Error(queries)
+	}
+}
+
+func TestSession_SQLServerSavepoint(t *testing.T) {
+	create, rollback, release := (&Session{dialect: SQLServer}).savepointSql("sp1")
+	if create == "SAVE TRANSACTION [sp1]" && rollback == "ROLLBACK TRANSACTION [sp1]" && release == "" {
+		t.Log(create, rollback)
+	} else {
+		t.Error(create, rollback, release)
+	}
+}
@@ -0,0 +1,111 @@
+package sqlBuilder
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SoftDeleteScope 软删除全局作用域的名称，可通过 WithoutScope(SoftDeleteScope) 移除
+const SoftDeleteScope = "softDelete"
+
+type trashedMode uint8
+
+const (
+	withoutTrashed trashedMode = iota
+	withTrashed
+	onlyTrashed
+)
+
+var (
+	softDeletesMu sync.RWMutex
+	softDeletes   = make(map[string]string)
+)
+
+// RegisterSoftDelete 为表开启软删除，column 为软删除时间字段，例如 deleted_at
+// 开启后 Delete 改为将 column 更新为当前时间，ToSql、Update 自动过滤已删除的记录
+func RegisterSoftDelete(table string, column string) {
+	softDeletesMu.Lock()
+	softDeletes[table] = column
+	softDeletesMu.Unlock()
+
+	RegisterGlobalScope(table, SoftDeleteScope, func(b *Builder) *Builder {
+		column := b.qualifyColumn(column)
+		switch b.methods.trashed {
+		case withTrashed:
+			return b
+		case onlyTrashed:
+			return b.WhereNotNull(column)
+		}
+		return b.WhereNull(column)
+	})
+}
+
+// RemoveSoftDelete 关闭表的软删除
+func RemoveSoftDelete(table string) {
+	softDeletesMu.Lock()
+	delete(softDeletes, table)
+	softDeletesMu.Unlock()
+
+	RemoveGlobalScope(table, SoftDeleteScope)
+}
+
+func softDeleteColumn(table string) string {
+	softDeletesMu.RLock()
+	defer softDeletesMu.RUnlock()
+
+	return softDeletes[table]
+}
+
+// qualifyColumn 有别名或关联查询时为字段加上表名前缀，避免字段歧义
+func (b *Builder) qualifyColumn(column string) string {
+	if b.tmpTable != "" && b.TableAlias != "" {
+		return b.TableAlias + "." + column
+	}
+	if len(b.methods.join) > 0 {
+		return b.scopeTable() + "." + column
+	}
+	return column
+}
+
+// WithTrashed 查询时包含已软删除的记录
+func (b *Builder) WithTrashed() *Builder {
+	b.methods.trashed = withTrashed
+	return b
+}
+
+// OnlyTrashed 只查询已软删除的记录
+func (b *Builder) OnlyTrashed() *Builder {
+	b.methods.trashed = onlyTrashed
+	return b
+}
+
+// Restore 恢复已软删除的记录
+func (b *Builder) Restore() (string, []interface{}) {
+	column := softDeleteColumn(b.scopeTable())
+	if column == "" {
+		defer b.cleanLastSql()
+		b.setErr(fmt.Errorf("%w: %s", ErrSoftDeleteDisabled, b.scopeTable()))
+		return b.result("", nil)
+	}
+
+	b.methods.trashed = onlyTrashed
+	return b.build("UPDATE", func() (string, []interface{}) {
+		return b.softDeleteSql(column, "NULL")
+	})
+}
+
+// softDeleteSql 通过 Update 将软删除字段更新为 value，同时维护时间字段和乐观锁，保留删除语句的排序和数量限制
+func (b *Builder) softDeleteSql(column string, value string) (string, []interface{}) {
+	sql, params := b.updateSql(map[string]interface{}{column: Raw(value)})
+
+	sql = b.builderOrder(sql)
+	sql = b.builderMutationLimit(sql)
+
+	return sql, params
+}
+
+// ForceDelete 物理删除记录，包括已软删除的记录
+func (b *Builder) ForceDelete() (string, []interface{}) {
+	b.methods.trashed = withTrashed
+	return b.build("DELETE", b.deleteSql)
+}
@@ -0,0 +1,120 @@
+package sqlBuilder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuilder_SoftDelete(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	RegisterSoftDelete("soft_user", "deleted_at")
+	defer RemoveSoftDelete("soft_user")
+
+	b := NewBuilder("soft_user")
+	sql, params = b.Where("id", 1).Delete()
+	if sql == "UPDATE `soft_user` SET `deleted_at`=NOW() WHERE (`id` = ?) AND `deleted_at` IS NULL" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Where("id", ">", 1).Order("id").Limit(1).Delete()
+	if sql == "UPDATE `soft_user` SET `deleted_at`=NOW() WHERE (`id` > ?) AND `deleted_at` IS NULL ORDER BY `id` DESC LIMIT 1" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Where("sex", 1).ToSql()
+	if sql == "SELECT * FROM `soft_user` WHERE (`sex` = ?) AND `deleted_at` IS NULL" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Table("soft_user u").LefJoin("contacts c", "c.user_id=u.id").WithTrashed().ToSql()
+	if sql == "SELECT * FROM `soft_user` as `u` LEFT JOIN `contacts` as `c` c.user_id=u.id" &&
+		reflect.DeepEqual(params, []interface{}{}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Table("soft_user u").OnlyTrashed().ToSql()
+	if sql == "SELECT * FROM `soft_user` as `u` WHERE `u`.`deleted_at` IS NOT NULL" &&
+		reflect.DeepEqual(params, []interface{}{}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Where("id", 1).Restore()
+	if sql == "UPDATE `soft_user` SET `deleted_at`=NULL WHERE (`id` = ?) AND `deleted_at` IS NOT NULL" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Where("id", 1).ForceDelete()
+	if sql == "delete from `soft_user` WHERE (`id` = ?)" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	b = NewBuilder("user")
+	sql, params = b.Where("id", 1).Restore()
+	if sql == "" && errors.Is(b.Err(), ErrSoftDeleteDisabled) {
+		t.Log(b.Err())
+	} else {
+		t.Error(sql, params, b.Err())
+	}
+}
+
+func TestBuilder_SoftDelete_Update(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	RegisterSoftDelete("soft_order", "deleted_at")
+	RegisterTimestamps("soft_order", Timestamps{CreatedAt: "created_at", UpdatedAt: "updated_at"})
+	RegisterVersion("soft_order", "version")
+	defer func() {
+		RemoveSoftDelete("soft_order")
+		RemoveTimestamps("soft_order")
+		RemoveVersion("soft_order")
+	}()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := NewBuilder("soft_order").SetClock(func() time.Time {
+		return now
+	})
+
+	sql, params = b.Where("id", 1).Version(3).Delete()
+	if sql == "UPDATE `soft_order` SET `deleted_at`=NOW(),`updated_at`=?,`version`=`version` + 1 WHERE ((`id` = ?) AND `deleted_at` IS NULL) AND `version` = ?" &&
+		reflect.DeepEqual(params, []interface{}{now, 1, 3}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.Where("id", 1).Restore()
+	if sql == "UPDATE `soft_order` SET `deleted_at`=NULL,`updated_at`=? WHERE (`id` = ?) AND `deleted_at` IS NOT NULL" &&
+		reflect.DeepEqual(params, []interface{}{now, 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
@@ -0,0 +1,243 @@
+package sqlBuilder
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+const (
+	// MetricStmtCacheHits 预处理语句缓存命中次数
+	MetricStmtCacheHits = "sql_stmt_cache_hits_total"
+	// MetricStmtCacheMisses 预处理语句缓存未命中次数
+	MetricStmtCacheMisses = "sql_stmt_cache_misses_total"
+	// MetricStmtCacheEvictions 预处理语句被淘汰的次数
+	MetricStmtCacheEvictions = "sql_stmt_cache_evictions_total"
+	// MetricStmtCacheInvalidations 预处理语句因表结构变更失效的次数
+	MetricStmtCacheInvalidations = "sql_stmt_cache_invalidations_total"
+)
+
+// Preparer 可以创建预处理语句的数据库连接，*sql.DB 实现了该接口
+type Preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// StmtCacheStats 预处理语句缓存的统计
+type StmtCacheStats struct {
+	Hits          int64
+	Misses        int64
+	Evictions     int64
+	Invalidations int64
+	Size          int
+}
+
+type stmtEntry struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// StmtCache 按SQL缓存预处理语句的 Executor，超出容量时淘汰最久未使用的语句。
+// 语句因表结构变更失效时重新预处理并重试一次
+type StmtCache struct {
+	db       Preparer
+	capacity int
+	dialect  *Dialect
+	sink     MetricsSink
+
+	mu    sync.Mutex
+	items map[string]*list.Element
+	lru   *list.List
+	stats StmtCacheStats
+}
+
+// NewStmtCache 为数据库连接池创建预处理语句缓存，capacity 为最多缓存的语句数量
+func NewStmtCache(db Preparer, capacity int) *StmtCache {
+	return &StmtCache{db: db, capacity: max(capacity, 1), items: make(map[string]*list.Element), lru: list.New()}
+}
+
+// SetDialect 指定识别表结构变更错误使用的方言，默认为 MySQL
+func (c *StmtCache) SetDialect(dialect *Dialect) *StmtCache {
+	c.dialect = dialect
+	return c
+}
+
+// Metrics 将命中、未命中、淘汰和失效次数记录到 sink
+func (c *StmtCache) Metrics(sink MetricsSink) *StmtCache {
+	c.sink = sink
+	return c
+}
+
+// Stats 返回缓存的统计
+func (c *StmtCache) Stats() StmtCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = c.lru.Len()
+	return stats
+}
+
+func (c *StmtCache) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := c.run(ctx, query, func(stmt *sql.Stmt) (err error) {
+		result, err = stmt.ExecContext(ctx, args...)
+		return err
+	})
+	return result, err
+}
+
+func (c *StmtCache) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := c.run(ctx, query, func(stmt *sql.Stmt) (err error) {
+		rows, err = stmt.QueryContext(ctx, args...)
+		return err
+	})
+	return rows, err
+}
+
+// BeginTx 开启事务，事务中的语句不使用缓存
+func (c *StmtCache) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	db, ok := c.db.(TxBeginner)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrTxUnsupported, c.db)
+	}
+	return db.BeginTx(ctx, opts)
+}
+
+// Close 关闭并清空所有缓存的语句
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	for c.lru.Len() > 0 {
+		if closeErr := c.remove(c.lru.Back()); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// run 使用缓存的语句执行 fn，语句因表结构变更失效时重新预处理后重试一次
+func (c *StmtCache) run(ctx context.Context, query string, fn func(stmt *sql.Stmt) error) error {
+	entry, err := c.acquire(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	err = fn(entry.stmt)
+	if err != nil && c.isSchemaChange(err) {
+		c.invalidate(entry)
+		c.release(entry)
+
+		if entry, err = c.acquire(ctx, query); err != nil {
+			return err
+		}
+		err = fn(entry.stmt)
+	}
+
+	c.release(entry)
+	return err
+}
+
+func (c *StmtCache) acquire(ctx context.Context, query string) (*stmtEntry, error) {
+	c.mu.Lock()
+	if element, ok := c.items[query]; ok {
+		c.lru.MoveToFront(element)
+		entry := element.Value.(*stmtEntry)
+		entry.refs++
+		c.stats.Hits++
+		c.mu.Unlock()
+
+		c.inc(MetricStmtCacheHits)
+		return entry, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+	c.inc(MetricStmtCacheMisses)
+
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 并发预处理了同一条语句时使用已缓存的语句
+	if element, ok := c.items[query]; ok {
+		stmt.Close()
+		entry := element.Value.(*stmtEntry)
+		entry.refs++
+		return entry, nil
+	}
+
+	entry := &stmtEntry{query: query, stmt: stmt, refs: 1}
+	c.items[query] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.capacity {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+		c.inc(MetricStmtCacheEvictions)
+	}
+
+	return entry, nil
+}
+
+// release 归还语句，已被移出缓存且没有使用者的语句会被关闭
+func (c *StmtCache) release(entry *stmtEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		entry.stmt.Close()
+	}
+}
+
+func (c *StmtCache) invalidate(entry *stmtEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[entry.query]; ok && element.Value == entry {
+		c.remove(element)
+		c.stats.Invalidations++
+		c.inc(MetricStmtCacheInvalidations)
+	}
+}
+
+// remove 移出缓存，语句正在使用时延迟到 release 关闭
+func (c *StmtCache) remove(element *list.Element) error {
+	entry := c.lru.Remove(element).(*stmtEntry)
+	delete(c.items, entry.query)
+	entry.evicted = true
+
+	if entry.refs == 0 {
+		return entry.stmt.Close()
+	}
+	return nil
+}
+
+func (c *StmtCache) isSchemaChange(err error) bool {
+	dialect := c.dialect
+	if dialect == nil {
+		dialect = MySQL
+	}
+
+	for _, code := range errorCodes(err) {
+		if slices.Contains(dialect.schemaCodes, code) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *StmtCache) inc(name string) {
+	if c.sink != nil {
+		c.sink.Inc(name, nil)
+	}
+}
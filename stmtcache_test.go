@@ -0,0 +1,103 @@
+package sqlBuilder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestStmtCache(t *testing.T) {
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	sink := NewMemorySink()
+	cache := NewStmtCache(db, 2).Metrics(sink)
+	defer cache.Close()
+
+	b := NewBuilder("user").SetExecutor(cache)
+	for _, id := range []int{1, 2} {
+		if _, err := b.Where("id", id).UpdateContext(ctx, map[string]interface{}{"name": "a", "age": 18}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.Where("id", 1).DeleteContext(ctx)
+	rows, err := b.Where("id", 1).QueryContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	b.Where("id", 1).UpdateContext(ctx, map[string]interface{}{"name": "a", "age": 18})
+
+	update := "UPDATE `user` SET `age`=?,`name`=? WHERE `id` = ?"
+	queries := fake.queries()
+	if reflect.DeepEqual(queries, []string{
+		"PREPARE " + update, update, update,
+		"PREPARE delete from `user` WHERE `id` = ?", "delete from `user` WHERE `id` = ?",
+		"PREPARE SELECT * FROM `user` WHERE `id` = ?", "SELECT * FROM `user` WHERE `id` = ?",
+		"PREPARE " + update, update,
+	}) {
+		t.Log(queries)
+	} else {
+		t.Error(queries)
+	}
+
+	if stats := cache.Stats(); stats != (StmtCacheStats{Hits: 1, Misses: 4, Evictions: 2, Size: 2}) {
+		t.Error(stats)
+	}
+	if n := sink.Counter(MetricStmtCacheEvictions, nil); n != 2 {
+		t.Error(n)
+	}
+}
+
+func TestStmtCache_Invalidate(t *testing.T) {
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	cache := NewStmtCache(db, 10)
+	defer cache.Close()
+
+	query := "SELECT * FROM `user` WHERE `id` = ?"
+	rows, err := cache.QueryContext(ctx, query, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+
+	fake.errs = []error{&mysqlError{Number: 1615, Message: "Prepared statement needs to be re-prepared"}}
+	rows, err = cache.QueryContext(ctx, query, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+
+	queries := fake.queries()
+	if reflect.DeepEqual(queries, []string{"PREPARE " + query, query, query, "PREPARE " + query, query}) {
+		t.Log(queries)
+	} else {
+		t.Error(queries)
+	}
+
+	if stats := cache.Stats(); stats != (StmtCacheStats{Hits: 1, Misses: 2, Invalidations: 1, Size: 1}) {
+		t.Error(stats)
+	}
+}
+
+func TestStmtCache_Transaction(t *testing.T) {
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	cache := NewStmtCache(db, 10)
+	defer cache.Close()
+
+	err := Transaction(ctx, cache, func(tx *Session) error {
+		_, err := tx.NewBuilder("user").InsertContext(ctx, map[string]interface{}{"id": 1})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if queries := fake.queries(); !reflect.DeepEqual(queries, []string{"BEGIN", "INSERT INTO `user` (`id`) VALUES(?)", "COMMIT"}) {
+		t.Error(queries)
+	}
+}
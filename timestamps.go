@@ -0,0 +1,87 @@
+package sqlBuilder
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// Timestamps 自动维护的时间字段，字段名为空时不维护
+type Timestamps struct {
+	CreatedAt string
+	UpdatedAt string
+}
+
+var (
+	timestampsMu sync.RWMutex
+	timestamps   = make(map[string]Timestamps)
+)
+
+// RegisterTimestamps 为表开启时间字段自动维护：Insert、Replace 时写入 CreatedAt 和 UpdatedAt，
+// Update 和 DuplicateKey 时写入 UpdatedAt，数据中已指定的字段不会被覆盖
+func RegisterTimestamps(table string, ts Timestamps) {
+	timestampsMu.Lock()
+	defer timestampsMu.Unlock()
+
+	timestamps[table] = ts
+}
+
+// RemoveTimestamps 关闭表的时间字段自动维护
+func RemoveTimestamps(table string) {
+	timestampsMu.Lock()
+	defer timestampsMu.Unlock()
+
+	delete(timestamps, table)
+}
+
+// SetClock 指定获取当前时间的函数，默认 time.Now
+func (b *Builder) SetClock(clock func() time.Time) *Builder {
+	b.clock = clock
+	return b
+}
+
+func (b *Builder) now() time.Time {
+	if b.clock == nil {
+		return time.Now()
+	}
+	return b.clock()
+}
+
+func (b *Builder) getTimestamps() Timestamps {
+	timestampsMu.RLock()
+	defer timestampsMu.RUnlock()
+
+	return timestamps[b.scopeTable()]
+}
+
+// touch 为数据补充时间字段，返回新的 map，不修改传入的数据
+func (b *Builder) touch(data map[string]interface{}, now time.Time, create bool) map[string]interface{} {
+	ts := b.getTimestamps()
+
+	columns := make([]string, 0, 2)
+	if create && ts.CreatedAt != "" {
+		columns = append(columns, ts.CreatedAt)
+	}
+	if ts.UpdatedAt != "" {
+		columns = append(columns, ts.UpdatedAt)
+	}
+
+	var touched map[string]interface{}
+	for _, column := range columns {
+		if _, ok := data[column]; ok {
+			continue
+		}
+		if touched == nil {
+			touched = maps.Clone(data)
+			if touched == nil {
+				touched = make(map[string]interface{}, len(columns))
+			}
+		}
+		touched[column] = now
+	}
+
+	if touched == nil {
+		return data
+	}
+	return touched
+}
@@ -0,0 +1,77 @@
+package sqlBuilder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuilder_Timestamps(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	RegisterTimestamps("ts_user", Timestamps{CreatedAt: "created_at", UpdatedAt: "updated_at"})
+	defer RemoveTimestamps("ts_user")
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := NewBuilder("ts_user").SetClock(func() time.Time {
+		return now
+	})
+
+	data := map[string]interface{}{"created_at": "2020-01-01"}
+	sql, params = b.Insert(data)
+	if (sql == "INSERT INTO `ts_user` (`created_at`,`updated_at`) VALUES(?,?)" &&
+		reflect.DeepEqual(params, []interface{}{"2020-01-01", now})) ||
+		(sql == "INSERT INTO `ts_user` (`updated_at`,`created_at`) VALUES(?,?)" &&
+			reflect.DeepEqual(params, []interface{}{now, "2020-01-01"})) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	if len(data) != 1 {
+		t.Error(data)
+	}
+
+	sql, params = b.Where("id", 1).Update(map[string]interface{}{})
+	if sql == "UPDATE `ts_user` SET `updated_at`=? WHERE `id` = ?" &&
+		reflect.DeepEqual(params, []interface{}{now, 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = b.DuplicateKey(map[string]interface{}{}).Insert(map[string]interface{}{
+		"created_at": now,
+		"updated_at": now,
+	})
+	if (sql == "INSERT INTO `ts_user` (`created_at`,`updated_at`) VALUES(?,?) ON DUPLICATE KEY UPDATE `updated_at`=?" ||
+		sql == "INSERT INTO `ts_user` (`updated_at`,`created_at`) VALUES(?,?) ON DUPLICATE KEY UPDATE `updated_at`=?") &&
+		reflect.DeepEqual(params, []interface{}{now, now, now}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_InsertBatches_Timestamps(t *testing.T) {
+	RegisterTimestamps("ts_user", Timestamps{CreatedAt: "created_at", UpdatedAt: "updated_at"})
+	defer RemoveTimestamps("ts_user")
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := NewBuilder("ts_user").MaxPlaceholders(4).SetClock(func() time.Time {
+		return now
+	})
+
+	statements := b.InsertBatches(0, map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2})
+	if b.Err() == nil && reflect.DeepEqual(statements, []Statement{
+		{Sql: "INSERT INTO `ts_user` (`created_at`,`id`,`updated_at`) VALUES(?,?,?)", Params: []interface{}{now, 1, now}},
+		{Sql: "INSERT INTO `ts_user` (`created_at`,`id`,`updated_at`) VALUES(?,?,?)", Params: []interface{}{now, 2, now}},
+	}) {
+		t.Log(statements)
+	} else {
+		t.Error(statements, b.Err())
+	}
+}
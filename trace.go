@@ -0,0 +1,186 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Attribute span 属性
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span 一次执行的追踪区间，可以适配 OpenTelemetry 的 trace.Span
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer 创建 span，返回的 ctx 携带该 span，可以适配 OpenTelemetry 的 trace.Tracer
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// TraceHook 为每条执行的语句创建 span，属性遵循 OpenTelemetry 数据库语义约定，
+// span 所在的 ctx 会传给数据库连接，驱动中创建的 span 会作为它的子 span
+type TraceHook struct {
+	NopHook
+	tracer Tracer
+}
+
+// traceSpanKey 在 ctx 中保存 TraceHook 创建的 span
+type traceSpanKey struct {
+	hook *TraceHook
+}
+
+// NewTraceHook 创建追踪钩子
+func NewTraceHook(tracer Tracer) *TraceHook {
+	return &TraceHook{tracer: tracer}
+}
+
+func (h *TraceHook) BeforeExec(ctx context.Context, e *QueryEvent) (context.Context, error) {
+	operation := sqlOperation(e)
+
+	name := operation
+	if e.Table != "" {
+		name += " " + e.Table
+	}
+
+	ctx, span := h.tracer.Start(ctx, name)
+	span.SetAttributes(
+		Attribute{Key: "db.system", Value: dbSystem(e.Dialect)},
+		Attribute{Key: "db.statement", Value: normalize(e.Sql, e.Dialect)},
+		Attribute{Key: "db.operation", Value: operation},
+		Attribute{Key: "db.sql.table", Value: e.Table},
+	)
+
+	return context.WithValue(ctx, traceSpanKey{h}, span), nil
+}
+
+func (h *TraceHook) AfterExec(ctx context.Context, e *QueryEvent, result sql.Result, err error, duration time.Duration) {
+	span, ok := ctx.Value(traceSpanKey{h}).(Span)
+	if !ok {
+		return
+	}
+
+	if result != nil {
+		if affected, err := result.RowsAffected(); err == nil {
+			span.SetAttributes(Attribute{Key: "db.rows_affected", Value: affected})
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// dbSystem 返回方言对应的 db.system 属性值
+func dbSystem(d *Dialect) string {
+	if d == nil {
+		d = MySQL
+	}
+
+	switch d.Name {
+	case Postgres.Name:
+		return "postgresql"
+	case SQLServer.Name:
+		return "mssql"
+	}
+	return d.Name
+}
+
+// sqlOperation 根据语句的第一个关键字返回 db.operation，WITH 等开头的语句使用构造时的类型
+func sqlOperation(e *QueryEvent) string {
+	word, _, _ := strings.Cut(strings.TrimSpace(e.Sql), " ")
+	word = strings.ToUpper(word)
+	if slices.Contains([]string{"SELECT", "INSERT", "UPDATE", "DELETE", "REPLACE", "MERGE"}, word) {
+		return word
+	}
+	return e.Operation
+}
+
+// RecordedSpan SpanRecorder 记录的 span
+type RecordedSpan struct {
+	Name       string
+	Attributes map[string]interface{}
+	Errors     []error
+	Start      time.Time
+	End        time.Time
+	Ended      bool
+}
+
+// SpanRecorder 在内存中记录 span 的 Tracer，用于测试
+type SpanRecorder struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// recordedSpanKey 在 ctx 中保存 SpanRecorder 创建的 span
+type recordedSpanKey struct{}
+
+// Start 创建并记录 span
+func (r *SpanRecorder) Start(ctx context.Context, name string) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	span := &RecordedSpan{Name: name, Attributes: make(map[string]interface{}), Start: time.Now()}
+	r.spans = append(r.spans, span)
+	return context.WithValue(ctx, recordedSpanKey{}, span), &recordingSpan{recorder: r, span: span}
+}
+
+// Spans 返回已记录的 span 的副本
+func (r *SpanRecorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	spans := make([]RecordedSpan, len(r.spans))
+	for k, span := range r.spans {
+		spans[k] = *span
+		spans[k].Attributes = maps.Clone(span.Attributes)
+		spans[k].Errors = slices.Clone(span.Errors)
+	}
+	return spans
+}
+
+// Reset 清空已记录的 span
+func (r *SpanRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = nil
+}
+
+type recordingSpan struct {
+	recorder *SpanRecorder
+	span     *RecordedSpan
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...Attribute) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	for _, attr := range attrs {
+		s.span.Attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.span.Errors = append(s.span.Errors, err)
+}
+
+func (s *recordingSpan) End() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.span.End, s.span.Ended = time.Now(), true
+}
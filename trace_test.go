@@ -0,0 +1,93 @@
+package sqlBuilder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// ctxExecutor 记录执行时收到的 ctx 中的 span 名称
+type ctxExecutor struct {
+	Executor
+	spans []string
+}
+
+func (e *ctxExecutor) record(ctx context.Context) {
+	if span, ok := ctx.Value(recordedSpanKey{}).(*RecordedSpan); ok {
+		e.spans = append(e.spans, span.Name)
+	}
+}
+
+func (e *ctxExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.record(ctx)
+	return e.Executor.ExecContext(ctx, query, args...)
+}
+
+func (e *ctxExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	e.record(ctx)
+	return e.Executor.QueryContext(ctx, query, args...)
+}
+
+func TestTraceHook(t *testing.T) {
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+
+	recorder := &SpanRecorder{}
+	executor := &ctxExecutor{Executor: db}
+	b := NewBuilder("User").SetDialect(Postgres).SetExecutor(executor).Hook(NewTraceHook(recorder))
+
+	rows, err := b.Where("id", 1).Where(Raw("name = 'secret'")).QueryContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+
+	fake.errs = []error{errors.New("failed")}
+	b.Where("id", 1).UpdateContext(ctx, map[string]interface{}{"name": "a"})
+
+	spans := recorder.Spans()
+	if len(spans) != 2 {
+		t.Fatal(spans)
+	}
+
+	if spans[0].Name == "SELECT User" && spans[0].Ended && len(spans[0].Errors) == 0 && reflect.DeepEqual(spans[0].Attributes, map[string]interface{}{
+		"db.system":    "postgresql",
+		"db.statement": `SELECT * FROM "User" WHERE "id" = ? AND NAME = ?`,
+		"db.operation": "SELECT",
+		"db.sql.table": "User",
+	}) {
+		t.Log(spans[0])
+	} else {
+		t.Error(spans[0])
+	}
+
+	if spans[1].Name == "UPDATE User" && spans[1].Ended && len(spans[1].Errors) == 1 && spans[1].Attributes["db.operation"] == "UPDATE" {
+		t.Log(spans[1])
+	} else {
+		t.Error(spans[1])
+	}
+
+	if reflect.DeepEqual(executor.spans, []string{"SELECT User", "UPDATE User"}) {
+		t.Log(executor.spans)
+	} else {
+		t.Error(executor.spans)
+	}
+}
+
+func TestSqlOperation(t *testing.T) {
+	RegisterSoftDelete("post", "deleted_at")
+	defer RemoveSoftDelete("post")
+
+	b := NewBuilder("post")
+	b.Where("id", 1).Delete()
+	if op := sqlOperation(b.execEvent("UPDATE `post` SET `deleted_at`=NOW() WHERE `id` = ?", nil)); op != "UPDATE" {
+		t.Error(op)
+	}
+
+	b.With("t", func(b *Builder) { b.Table("post") }).Table("t").ToSql()
+	if op := sqlOperation(b.execEvent("WITH `t` AS (SELECT * FROM `post`) SELECT * FROM `t`", nil)); op != "SELECT" {
+		t.Error(op)
+	}
+}
@@ -0,0 +1,141 @@
+package sqlBuilder
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Upsert 插入记录，与 conflictColumns 冲突时更新 updateColumns，updateColumns 为 nil 时更新除冲突字段和创建时间字段外的所有字段。
+// 按方言生成 ON DUPLICATE KEY UPDATE（MySQL）、ON CONFLICT DO UPDATE（Postgres、SQLite）或 MERGE（SQLServer）
+func (b *Builder) Upsert(rows []map[string]interface{}, conflictColumns []string, updateColumns []string) (string, []interface{}) {
+	return b.build("INSERT", func() (string, []interface{}) {
+		return b.upsert(rows, conflictColumns, updateColumns, false)
+	})
+}
+
+// InsertIgnore 插入记录，忽略冲突的行。
+// 按方言生成 INSERT IGNORE（MySQL）、ON CONFLICT DO NOTHING（Postgres）或 INSERT OR IGNORE（SQLite），
+// SQLServer 需要通过 UpsertIgnore 指定冲突字段
+func (b *Builder) InsertIgnore(rows ...map[string]interface{}) (string, []interface{}) {
+	return b.build("INSERT", func() (string, []interface{}) {
+		return b.upsert(rows, nil, nil, true)
+	})
+}
+
+// UpsertIgnore 插入记录，与 conflictColumns 冲突的行不做处理
+func (b *Builder) UpsertIgnore(rows []map[string]interface{}, conflictColumns []string) (string, []interface{}) {
+	return b.build("INSERT", func() (string, []interface{}) {
+		return b.upsert(rows, conflictColumns, nil, true)
+	})
+}
+
+func (b *Builder) upsert(rows []map[string]interface{}, conflictColumns []string, updateColumns []string, ignore bool) (string, []interface{}) {
+	args := make([]interface{}, len(rows))
+	for k, row := range rows {
+		args[k] = row
+	}
+
+	if conflictColumns == nil {
+		conflictColumns = []string{}
+	}
+	field, values := b.insertRows(args, b.now(), conflictColumns)
+	if len(values) == 0 {
+		b.setErr(ErrInvalidRows)
+		return "", nil
+	}
+
+	if !ignore {
+		ts := b.getTimestamps()
+		if updateColumns == nil {
+			// 冲突时保留原记录的创建时间
+			updateColumns = slices.DeleteFunc(slices.Clone(field), func(f string) bool {
+				return slices.Contains(conflictColumns, f) || (ts.CreatedAt != "" && f == ts.CreatedAt)
+			})
+		} else if ts.UpdatedAt != "" && !slices.Contains(updateColumns, ts.UpdatedAt) {
+			updateColumns = append(slices.Clone(updateColumns), ts.UpdatedAt)
+		}
+		ignore = len(updateColumns) == 0
+	}
+
+	valuesSql, params := b.insertValues(values)
+	table := b.GetTable()
+	columns := b.escapeId(field)
+
+	switch b.GetDialect().Name {
+	case MySQL.Name:
+		if ignore {
+			return fmt.Sprintf("INSERT IGNORE INTO %s (%s) VALUES%s", table, columns, valuesSql), params
+		}
+
+		sets := make([]string, len(updateColumns))
+		for k, column := range updateColumns {
+			column = b.escapeId(column)
+			sets[k] = fmt.Sprintf("%s=VALUES(%s)", column, column)
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES%s ON DUPLICATE KEY UPDATE %s", table, columns, valuesSql, strings.Join(sets, ",")), params
+	case SQLServer.Name:
+		return b.merge(table, field, valuesSql, params, conflictColumns, updateColumns, ignore)
+	case SQLite.Name:
+		if ignore && len(conflictColumns) == 0 {
+			return fmt.Sprintf("INSERT OR IGNORE INTO %s (%s) VALUES%s", table, columns, valuesSql), params
+		}
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES%s ON CONFLICT", table, columns, valuesSql)
+	if len(conflictColumns) > 0 {
+		sql += fmt.Sprintf(" (%s)", b.escapeId(conflictColumns))
+	}
+
+	if ignore {
+		return sql + " DO NOTHING", params
+	}
+
+	if len(conflictColumns) == 0 {
+		b.setErr(ErrConflictColumnsRequired)
+		return "", nil
+	}
+
+	sets := make([]string, len(updateColumns))
+	for k, column := range updateColumns {
+		column = b.escapeId(column)
+		sets[k] = fmt.Sprintf("%s=EXCLUDED.%s", column, column)
+	}
+	return sql + " DO UPDATE SET " + strings.Join(sets, ","), params
+}
+
+// merge 生成 SQLServer 的 MERGE 语句
+func (b *Builder) merge(table string, field []string, valuesSql string, params []interface{}, conflictColumns []string, updateColumns []string, ignore bool) (string, []interface{}) {
+	if len(conflictColumns) == 0 {
+		b.setErr(ErrConflictColumnsRequired)
+		return "", nil
+	}
+
+	target, source := b.quote("target"), b.quote("source")
+
+	on := make([]string, len(conflictColumns))
+	for k, column := range conflictColumns {
+		column = b.escapeId(column)
+		on[k] = fmt.Sprintf("%s.%s = %s.%s", target, column, source, column)
+	}
+
+	sql := fmt.Sprintf("MERGE INTO %s AS %s USING (VALUES %s) AS %s (%s) ON %s",
+		table, target, valuesSql, source, b.escapeId(field), strings.Join(on, " AND "))
+
+	if !ignore {
+		sets := make([]string, len(updateColumns))
+		for k, column := range updateColumns {
+			column = b.escapeId(column)
+			sets[k] = fmt.Sprintf("%s.%s=%s.%s", target, column, source, column)
+		}
+		sql += " WHEN MATCHED THEN UPDATE SET " + strings.Join(sets, ",")
+	}
+
+	sourceColumns := make([]string, len(field))
+	for k, column := range field {
+		sourceColumns[k] = source + "." + b.escapeId(column)
+	}
+	sql += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);", b.escapeId(field), strings.Join(sourceColumns, ","))
+
+	return sql, params
+}
@@ -0,0 +1,122 @@
+package sqlBuilder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuilder_Upsert(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "张三", "age": 18},
+		{"id": 2, "name": "李四", "age": 30},
+	}
+
+	sql, params = NewBuilder("user").Upsert(rows, []string{"id"}, nil)
+	if sql == "INSERT INTO `user` (`id`,`age`,`name`) VALUES(?,?,?),(?,?,?) ON DUPLICATE KEY UPDATE `age`=VALUES(`age`),`name`=VALUES(`name`)" &&
+		reflect.DeepEqual(params, []interface{}{1, 18, "张三", 2, 30, "李四"}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").SetDialect(Postgres).Upsert(rows, []string{"id"}, []string{"name"})
+	if sql == `INSERT INTO "user" ("id","age","name") VALUES(?,?,?),(?,?,?) ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name"` &&
+		reflect.DeepEqual(params, []interface{}{1, 18, "张三", 2, 30, "李四"}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").SetDialect(SQLServer).Upsert(rows[:1], []string{"id"}, []string{"name"})
+	if sql == "MERGE INTO [user] AS [target] USING (VALUES (?,?,?)) AS [source] ([id],[age],[name]) ON [target].[id] = [source].[id]"+
+		" WHEN MATCHED THEN UPDATE SET [target].[name]=[source].[name]"+
+		" WHEN NOT MATCHED THEN INSERT ([id],[age],[name]) VALUES ([source].[id],[source].[age],[source].[name]);" &&
+		reflect.DeepEqual(params, []interface{}{1, 18, "张三"}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	b := NewBuilder("user").SetDialect(Postgres)
+	sql, params = b.Upsert(rows, nil, nil)
+	if sql == "" && errors.Is(b.Err(), ErrConflictColumnsRequired) {
+		t.Log(b.Err())
+	} else {
+		t.Error(sql, params, b.Err())
+	}
+
+	for _, rows := range [][]map[string]interface{}{nil, {{}}} {
+		b = NewBuilder("user")
+		sql, params = b.Upsert(rows, []string{"id"}, nil)
+		if sql == "" && errors.Is(b.Err(), ErrInvalidRows) {
+			t.Log(b.Err())
+		} else {
+			t.Error(sql, params, b.Err())
+		}
+	}
+}
+
+func TestBuilder_Upsert_Timestamps(t *testing.T) {
+	RegisterTimestamps("ts_upsert", Timestamps{CreatedAt: "created_at", UpdatedAt: "updated_at"})
+	defer RemoveTimestamps("ts_upsert")
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sql, params := NewBuilder("ts_upsert").SetClock(func() time.Time {
+		return now
+	}).Upsert([]map[string]interface{}{{"id": 1, "name": "张三"}}, []string{"id"}, nil)
+	if sql == "INSERT INTO `ts_upsert` (`id`,`created_at`,`name`,`updated_at`) VALUES(?,?,?,?)"+
+		" ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`updated_at`=VALUES(`updated_at`)" &&
+		reflect.DeepEqual(params, []interface{}{1, now, "张三", now}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_InsertIgnore(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	row := map[string]interface{}{"id": 1}
+
+	sql, params = NewBuilder("user").InsertIgnore(row)
+	if sql == "INSERT IGNORE INTO `user` (`id`) VALUES(?)" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").SetDialect(Postgres).InsertIgnore(row)
+	if sql == `INSERT INTO "user" ("id") VALUES(?) ON CONFLICT DO NOTHING` &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").SetDialect(SQLite).InsertIgnore(row)
+	if sql == `INSERT OR IGNORE INTO "user" ("id") VALUES(?)` &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").SetDialect(SQLServer).UpsertIgnore([]map[string]interface{}{row}, []string{"id"})
+	if sql == "MERGE INTO [user] AS [target] USING (VALUES (?)) AS [source] ([id]) ON [target].[id] = [source].[id] WHEN NOT MATCHED THEN INSERT ([id]) VALUES ([source].[id]);" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
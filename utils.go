@@ -3,33 +3,112 @@ package sqlBuilder
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 )
 
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
 func (b *Builder) initialize() {
 	if b.params == nil {
 		b.params = make(map[string][]interface{}, 4)
 	}
 }
 
+// newSubBuilder 创建继承方言和严格模式的子查询构造器
+func (b *Builder) newSubBuilder() *Builder {
+	bw := NewBuilder("")
+	bw.dialect = b.dialect
+	bw.strict = b.strict
+	bw.maxPlaceholders = b.maxPlaceholders
+	bw.inChunkSize = b.inChunkSize
+	bw.clock = b.clock
+	bw.executor = b.executor
+	return bw
+}
+
+// subQuery 构造子查询，支持 func(*Builder)、func() *Builder 和 *Builder
+func (b *Builder) subQuery(query interface{}) (string, []interface{}) {
+	var bw *Builder
+	switch query := query.(type) {
+	case func(*Builder):
+		bw = b.newSubBuilder()
+		query(bw)
+	case func() *Builder:
+		bw = query()
+	case *Builder:
+		bw = query
+	default:
+		b.setErr(fmt.Errorf("%w: %T", ErrInvalidSubQuery, query))
+		return "", nil
+	}
+
+	sql, params := bw.subQuerySql()
+	b.setErr(bw.Err())
+	return sql, params
+}
+
+func (b *Builder) setErr(err error) {
+	if err != nil && b.methods.err == nil {
+		b.methods.err = err
+	}
+}
+
+// checkId 严格模式下校验标识符
+func (b *Builder) checkId(id string) {
+	if b.strict && id != "*" && !identifierPattern.MatchString(id) {
+		b.setErr(fmt.Errorf("%w: %q", ErrInvalidIdentifier, id))
+	}
+}
+
+func (b *Builder) quote(id string) string {
+	b.checkId(id)
+	if id == "*" {
+		return id
+	}
+	return b.GetDialect().Quote(id)
+}
+
+func (b *Builder) placeholderLimit() int {
+	if b.maxPlaceholders != 0 {
+		return max(b.maxPlaceholders, 0)
+	}
+	return b.GetDialect().maxParams
+}
+
+// result 链式操作中产生错误时不返回SQL
+func (b *Builder) result(sql string, params []interface{}) (string, []interface{}) {
+	if limit := b.placeholderLimit(); limit > 0 && len(params) > limit {
+		b.setErr(fmt.Errorf("%w: %d > %d", ErrTooManyPlaceholders, len(params), limit))
+	}
+
+	if b.methods.err != nil {
+		return "", nil
+	}
+	return sql, params
+}
+
 func (b *Builder) setTable(table interface{}) (tmpTableClosureCount uint8, tmpTable string, param []interface{}, tableAlias string) {
 	switch table.(type) {
 	case string:
 		tmpTable, tableAlias = b.getAlias(table.(string))
 	case func(*Builder):
-		bw := NewBuilder("")
+		bw := b.newSubBuilder()
 		bw.tmpTableClosureCount = b.tmpTableClosureCount
 		bw.tmpTableClosureCount++
 		tmpTableClosureCount = bw.tmpTableClosureCount
 		table.(func(*Builder))(bw)
-		tmpTable, param = bw.ToSql()
+		tmpTable, param = bw.subQuerySql()
+		b.setErr(bw.Err())
 		tableAlias = fmt.Sprintf("tmp%d", tmpTableClosureCount)
-		tmpTable = fmt.Sprintf("(%s) as `tmp%d`", tmpTable, tmpTableClosureCount)
+		tmpTable = fmt.Sprintf("(%s) as %s", tmpTable, b.quote(tableAlias))
 	case func() *Builder:
 		tmpTableClosureCount = b.tmpTableClosureCount + 1
-		tmpTable, param = table.(func() *Builder)().ToSql()
+		bw := table.(func() *Builder)()
+		tmpTable, param = bw.subQuerySql()
+		b.setErr(bw.Err())
 		tableAlias = fmt.Sprintf("tmp%d", tmpTableClosureCount)
-		tmpTable = fmt.Sprintf("(%s) as `tmp%d`", tmpTable, tmpTableClosureCount)
+		tmpTable = fmt.Sprintf("(%s) as %s", tmpTable, b.quote(tableAlias))
 	}
 
 	return tmpTableClosureCount, tmpTable, param, tableAlias
@@ -47,47 +126,43 @@ func (b *Builder) placeholders(n int) string {
 }
 
 func (b *Builder) escapeId(field interface{}) (fieldStr string) {
-	if field, ok := field.(Raw); ok {
-		fieldStr += fmt.Sprintf("%s", field)
-		return
-	}
-
-	comma := ""
-	var fieldArr []string
-	if field, ok := field.(string); ok {
-		fieldArr = strings.Split(field, ",")
-	}
+	var fieldArr []interface{}
 
-	if field, ok := field.([]string); ok {
+	switch field := field.(type) {
+	case Raw:
+		return string(field)
+	case string:
+		for _, v := range splitFields(field, b.GetDialect()) {
+			fieldArr = append(fieldArr, v)
+		}
+	case []string:
+		for _, v := range field {
+			fieldArr = append(fieldArr, v)
+		}
+	case []interface{}:
 		fieldArr = field
 	}
 
-	if len(fieldArr) > 0 {
-		for k, v := range fieldArr {
-			if k > 0 {
-				comma = ","
-			}
-			fieldStr += b.strEscapeId(v, comma)
+	fields := make([]string, 0, len(fieldArr))
+	for _, v := range fieldArr {
+		switch v := v.(type) {
+		case string:
+			fields = append(fields, b.escapeExpr(v))
+		case Raw:
+			fields = append(fields, string(v))
 		}
-		return
 	}
 
-	if field, ok := field.([]interface{}); ok {
-		for k, v := range field {
-			if k > 0 {
-				comma = ","
-			}
-			switch v.(type) {
-			case string:
-				fieldStr += b.strEscapeId(v.(string), comma)
-			case Raw:
-				fieldStr += fmt.Sprintf("%s%s", comma, v)
-			}
-		}
-		return
-	}
+	return strings.Join(fields, ",")
+}
 
-	return
+// column 条件、排序和分组字段，支持标识符和函数调用，其他表达式需使用 Raw
+func (b *Builder) column(field interface{}) string {
+	if raw, ok := field.(Raw); ok {
+		return string(raw)
+	}
+	name, _ := field.(string)
+	return b.escapeColumn(name)
 }
 
 func (b *Builder) getAlias(field string) (string, string) {
@@ -118,41 +193,12 @@ func (b *Builder) getAlias(field string) (string, string) {
 	return field, alias
 }
 
-func (b *Builder) strEscapeId(field string, comma string) string {
-	var alias, table string
-
-	field, alias = b.getAlias(field)
-
-	if alias != "" {
-		alias = " as `" + alias + "`"
-	}
-
-	if strings.Contains(field, ".") {
-		fieldArr := strings.Split(field, ".")
-		table = strings.Trim(fieldArr[0], " ")
-		field = strings.Trim(fieldArr[1], " ")
-	}
-
-	if table != "" {
-		table = "`" + table + "`."
-	}
-
-	leftBracketIndex := strings.Index(field, "(")
-	rightBracketIndex := strings.Index(field, ")")
-
-	if leftBracketIndex >= 0 && rightBracketIndex >= 0 {
-		param := strings.Trim(field[leftBracketIndex+1:rightBracketIndex], " `")
-		field = field[:leftBracketIndex]
-
-		if param != "" && param != "*" {
-			param = fmt.Sprintf("`%s`", param)
-		}
-		field = fmt.Sprintf("%s(%s)", field, param)
-	} else {
-		field = fmt.Sprintf("`%s`", field)
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
 	}
-
-	return fmt.Sprintf("%s%s%s%s", comma, table, field, alias)
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Ptr && v.IsNil()
 }
 
 func (b *Builder) convertInterfaceSlice(arr interface{}) []interface{} {
@@ -173,72 +219,106 @@ func (b *Builder) conditions(mode string, boolean string, args ...interface{}) *
 	argsLen := len(args)
 	if argsLen == 1 {
 		if query, ok := args[0].(func(*Builder)); ok {
-			bw := NewBuilder("")
+			bw := b.newSubBuilder()
 			query(bw)
-			conditions = fmt.Sprintf(" %s (%s)", boolean, strings.Join(bw.methods.where, ""))
-			b.params[mode] = append(b.params[mode], bw.params[mode]...)
+			b.setErr(bw.Err())
+			// 闭包内没有条件时不生成空括号
+			if len(bw.methods.where) > 0 {
+				conditions = fmt.Sprintf(" %s (%s)", boolean, strings.Trim(strings.Join(bw.methods.where, ""), " "))
+				b.params[mode] = append(b.params[mode], bw.params[mode]...)
+			}
 		} else if condition, ok := args[0].(Raw); ok {
 			conditions = fmt.Sprintf(" %s %s", boolean, condition)
 		}
 	} else if argsLen > 1 {
-		field := ""
-		operator := ""
+		field := args[0]
+		operator := "="
 
 		var value interface{}
 		switch argsLen {
 		case 2:
-			field = args[0].(string)
-			operator = "="
 			value = args[1]
 		case 3:
-			field = args[0].(string)
 			operator = args[1].(string)
 			value = args[2]
 		default:
-			field = args[0].(string)
 			operator = args[1].(string)
 			value = args[2:]
 		}
 
+		if isNil(value) {
+			value = IsNull
+			if op := strings.ToUpper(strings.TrimSpace(operator)); op == "!=" || op == "<>" || op == "IS NOT" {
+				value = IsNotNull
+			}
+		}
+
 		valueKind := reflect.TypeOf(value).Kind()
 
 		if strings.Contains(operator, "BETWEEN") {
 			args = b.convertInterfaceSlice(value)
 
 			b.params[mode] = append(b.params[mode], args[:2]...)
-			conditions = fmt.Sprintf(" %s %s %s ? AND ?", boolean, b.escapeId(field), operator)
+			conditions = fmt.Sprintf(" %s %s %s ? AND ?", boolean, b.column(field), operator)
 		} else {
 			switch valueKind {
-			case reflect.Array:
-			case reflect.Slice:
+			case reflect.Array, reflect.Slice:
 				vi := b.convertInterfaceSlice(value)
-				conditions = fmt.Sprintf(" %s %s %s (%s)", boolean, b.escapeId(field), operator, b.placeholders(len(vi)))
+				if len(vi) == 0 {
+					// 空列表：IN 恒为假，NOT IN 恒为真
+					if strings.Contains(strings.ToUpper(operator), "NOT") {
+						conditions = fmt.Sprintf(" %s 1 = 1", boolean)
+					} else {
+						conditions = fmt.Sprintf(" %s 1 = 0", boolean)
+					}
+					break
+				}
+				if size := b.inChunkSize; size > 0 && len(vi) > size {
+					glue := " OR "
+					if strings.Contains(strings.ToUpper(operator), "NOT") {
+						glue = " AND "
+					}
+					groups := make([]string, 0, (len(vi)+size-1)/size)
+					for i := 0; i < len(vi); i += size {
+						n := min(size, len(vi)-i)
+						groups = append(groups, fmt.Sprintf("%s %s (%s)", b.column(field), operator, b.placeholders(n)))
+					}
+					conditions = fmt.Sprintf(" %s (%s)", boolean, strings.Join(groups, glue))
+				} else {
+					conditions = fmt.Sprintf(" %s %s %s (%s)", boolean, b.column(field), operator, b.placeholders(len(vi)))
+				}
 				b.params[mode] = append(b.params[mode], vi...)
 			case reflect.Func:
 				if query, ok := value.(func(*Builder)); ok {
-					bw := NewBuilder("")
+					bw := b.newSubBuilder()
 					query(bw)
-					bwSql, bwParams := bw.ToSql()
+					bwSql, bwParams := bw.subQuerySql()
+					b.setErr(bw.Err())
 					if field == "EXISTS" || field == "NOT EXISTS" {
-						operator = field
-						conditions = fmt.Sprintf(" %s %s (%s)", boolean, operator, bwSql)
+						conditions = fmt.Sprintf(" %s %s (%s)", boolean, field, bwSql)
 					} else {
-						conditions = fmt.Sprintf(" %s %s %s (%s)", boolean, b.escapeId(field), operator, bwSql)
+						conditions = fmt.Sprintf(" %s %s %s (%s)", boolean, b.column(field), operator, bwSql)
 					}
 
 					b.params[mode] = append(b.params[mode], bwParams...)
 				}
 			default:
-				if value == "NULL" || value == "NOT NULL" {
-					conditions = fmt.Sprintf(" %s %s IS %s", boolean, b.escapeId(field), value)
+				if null, ok := value.(NullValue); ok {
+					conditions = fmt.Sprintf(" %s %s IS %s", boolean, b.column(field), null)
 				} else {
-					conditions = fmt.Sprintf(" %s %s %s ?", boolean, b.escapeId(field), operator)
+					conditions = fmt.Sprintf(" %s %s %s ?", boolean, b.column(field), operator)
 					b.params[mode] = append(b.params[mode], value)
 				}
 			}
 		}
 	}
 
+	if conditions == "" {
+		return b
+	}
+	// 第一个条件没有连接词，去掉多余的空格
+	conditions = " " + strings.TrimLeft(conditions, " ")
+
 	switch mode {
 	case "where":
 		b.methods.where = append(b.methods.where, conditions)
@@ -250,6 +330,7 @@ func (b *Builder) conditions(mode string, boolean string, args ...interface{}) *
 }
 
 func (b *Builder) cleanLastSql() {
+	b.lastErr = b.methods.err
 	b.tmpTable = ""
 	b.tmpTableClosureCount = 0
 	b.methods = methods{}
@@ -0,0 +1,62 @@
+package sqlBuilder
+
+import (
+	"maps"
+	"sync"
+)
+
+var (
+	versionsMu sync.RWMutex
+	versions   = make(map[string]string)
+)
+
+// RegisterVersion 为表开启乐观锁，通过 Version 指定当前版本号后
+// Update 只更新版本号一致的记录，并将版本字段加 1
+func RegisterVersion(table string, column string) {
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+
+	versions[table] = column
+}
+
+// RemoveVersion 关闭表的乐观锁
+func RemoveVersion(table string) {
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+
+	delete(versions, table)
+}
+
+func versionColumn(table string) string {
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+
+	return versions[table]
+}
+
+// Version 指定记录读取时的版本号，UpdateContext 影响行数为 0 时返回 ErrStaleRecord
+func (b *Builder) Version(version interface{}) *Builder {
+	b.methods.version = version
+	return b
+}
+
+// lockVersion 指定了版本号时为更新数据追加版本号自增，并追加版本号条件
+func (b *Builder) lockVersion(data map[string]interface{}) map[string]interface{} {
+	column := versionColumn(b.scopeTable())
+	if column == "" || b.methods.version == nil {
+		return data
+	}
+
+	if _, ok := data[column]; !ok {
+		data = maps.Clone(data)
+		if data == nil {
+			data = make(map[string]interface{}, 1)
+		}
+		data[column] = Raw(b.quoteName(column) + " + 1")
+	}
+
+	b.groupWhere()
+	b.Where(b.qualifyColumn(column), b.methods.version)
+
+	return data
+}
@@ -0,0 +1,72 @@
+package sqlBuilder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_Version(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	RegisterVersion("ver_order", "version")
+	defer RemoveVersion("ver_order")
+
+	sql, params = NewBuilder("ver_order").Where("id", 1).OrWhere("id", 2).Version(3).Update(map[string]interface{}{})
+	if sql == "UPDATE `ver_order` SET `version`=`version` + 1 WHERE (`id` = ? OR `id` = ?) AND `version` = ?" &&
+		reflect.DeepEqual(params, []interface{}{1, 2, 3}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("ver_order").Where("id", 1).Update(map[string]interface{}{"version": 10})
+	if sql == "UPDATE `ver_order` SET `version`=? WHERE `id` = ?" &&
+		reflect.DeepEqual(params, []interface{}{10, 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("ver_order").Where(Raw("id = 1 OR id = 2")).Version(3).Update(map[string]interface{}{"status": 2})
+	if sql == "UPDATE `ver_order` SET `status`=?,`version`=`version` + 1 WHERE (id = 1 OR id = 2) AND `version` = ?" &&
+		reflect.DeepEqual(params, []interface{}{2, 3}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("ver_order").Where("id", 1).Update(map[string]interface{}{"status": 2})
+	if sql == "UPDATE `ver_order` SET `status`=? WHERE `id` = ?" &&
+		reflect.DeepEqual(params, []interface{}{2, 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_Version_Stale(t *testing.T) {
+	RegisterVersion("ver_order", "version")
+	defer RemoveVersion("ver_order")
+
+	db, fake := openFakeDB(t)
+	ctx := context.Background()
+	b := NewBuilder("ver_order").SetExecutor(db)
+
+	if _, err := b.Where("id", 1).Version(3).UpdateContext(ctx, map[string]interface{}{}); err != nil {
+		t.Error(err)
+	}
+
+	fake.rowsAffected = 0
+	if _, err := b.Where("id", 1).Version(3).UpdateContext(ctx, map[string]interface{}{}); !errors.Is(err, ErrStaleRecord) {
+		t.Error(err)
+	}
+
+	if _, err := b.Where("id", 1).UpdateContext(ctx, map[string]interface{}{"status": 2}); err != nil {
+		t.Error(err)
+	}
+}
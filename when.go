@@ -0,0 +1,37 @@
+package sqlBuilder
+
+import "reflect"
+
+// When 条件为真时执行 fn，用于在链式操作中按条件追加查询条件
+func (b *Builder) When(condition bool, fn func(*Builder)) *Builder {
+	if condition {
+		fn(b)
+	}
+	return b
+}
+
+// Unless 条件为假时执行 fn
+func (b *Builder) Unless(condition bool, fn func(*Builder)) *Builder {
+	return b.When(!condition, fn)
+}
+
+// WhenNotZero value 不是零值时执行 fn，并把 value 传给 fn；nil、空字符串、空切片和空 map 都视为零值
+func (b *Builder) WhenNotZero(value interface{}, fn func(*Builder, interface{})) *Builder {
+	if !isZero(value) {
+		fn(b, value)
+	}
+	return b
+}
+
+func isZero(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,86 @@
+package sqlBuilder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_When(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").
+		When(false, func(m *Builder) {
+			m.Where("name", "like", "%q%")
+		}).
+		When(true, func(m *Builder) {
+			m.Where("sex", 1)
+		}).
+		Unless(false, func(m *Builder) {
+			m.OrWhere("age", ">", 18)
+		}).
+		ToSql()
+
+	if sql == "SELECT * FROM `user` WHERE `sex` = ? OR `age` > ?" &&
+		reflect.DeepEqual(params, []interface{}{1, 18}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_WhenNotZero(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").
+		WhenNotZero("", func(m *Builder, v interface{}) {
+			m.Where("name", v)
+		}).
+		WhenNotZero([]int{}, func(m *Builder, v interface{}) {
+			m.WhereIn("id", v)
+		}).
+		WhenNotZero(18, func(m *Builder, v interface{}) {
+			m.Where("age", v)
+		}).
+		ToSql()
+
+	if sql == "SELECT * FROM `user` WHERE `age` = ?" &&
+		reflect.DeepEqual(params, []interface{}{18}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_When_Closure(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").
+		Where("sex", 1).
+		OrWhere(func(m *Builder) {
+			m.When(false, func(m *Builder) {
+				m.Where("age", ">", 18)
+			})
+		}).
+		Where(func(m *Builder) {
+			m.When(true, func(m *Builder) {
+				m.Where("age", ">", 18)
+			}).OrWhere("vip", 1)
+		}).
+		ToSql()
+
+	if sql == "SELECT * FROM `user` WHERE `sex` = ? AND (`age` > ? OR `vip` = ?)" &&
+		reflect.DeepEqual(params, []interface{}{1, 18, 1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
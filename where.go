@@ -66,7 +66,7 @@ func (b *Builder) WhereIn(field string, value ...interface{}) *Builder {
 	args[0] = field
 	args[1] = "IN"
 
-	args = append(args, value...)
+	args = append(args, inValues(value)...)
 
 	return b.Where(args...)
 }
@@ -76,7 +76,7 @@ func (b *Builder) WhereNotIn(field string, value ...interface{}) *Builder {
 	args[0] = field
 	args[1] = "NOT IN"
 
-	args = append(args, value...)
+	args = append(args, inValues(value)...)
 
 	return b.Where(args...)
 }
@@ -86,7 +86,7 @@ func (b *Builder) OrWhereIn(field string, value ...interface{}) *Builder {
 	args[0] = field
 	args[1] = "IN"
 
-	args = append(args, value...)
+	args = append(args, inValues(value)...)
 
 	return b.OrWhere(args...)
 }
@@ -97,25 +97,33 @@ func (b *Builder) OrWhereNotIn(field string, value ...interface{}) *Builder {
 	args[0] = field
 	args[1] = "NOT IN"
 
-	args = append(args, value...)
+	args = append(args, inValues(value)...)
 
 	return b.OrWhere(args...)
 }
 
+// inValues 未传值时按空列表处理
+func inValues(value []interface{}) []interface{} {
+	if len(value) == 0 {
+		return []interface{}{[]interface{}{}}
+	}
+	return value
+}
+
 func (b *Builder) WhereNull(field string) *Builder {
-	return b.Where(field, "NULL")
+	return b.Where(field, IsNull)
 }
 
 func (b *Builder) WhereNotNull(field string) *Builder {
-	return b.Where(field, "NOT NULL")
+	return b.Where(field, IsNotNull)
 }
 
 func (b *Builder) OrWhereNull(field string) *Builder {
-	return b.OrWhere(field, "NULL")
+	return b.OrWhere(field, IsNull)
 }
 
 func (b *Builder) OrWhereNotNull(field string) *Builder {
-	return b.OrWhere(field, "NOT NULL")
+	return b.OrWhere(field, IsNotNull)
 }
 
 func (b *Builder) WhereBetween(field string, value ...interface{}) *Builder {
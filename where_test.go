@@ -53,7 +53,7 @@ func TestBuilder_Where_Closure(t *testing.T) {
 				Where("name", "like", "%q%")
 		}).ToSql()
 
-	if sql == "SELECT `id` FROM `user` WHERE `id` <> ? OR (  `age` > ? AND `name` like ?)" &&
+	if sql == "SELECT `id` FROM `user` WHERE `id` <> ? OR (`age` > ? AND `name` like ?)" &&
 		reflect.DeepEqual(params, []interface{}{1, 18, "%q%"}) {
 		t.Log(sql, params)
 	} else {
@@ -440,3 +440,74 @@ func TestBuilder_OrWhereNotBetween(t *testing.T) {
 		t.Error(sql, params)
 	}
 }
+
+func TestBuilder_WhereIn_Empty(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").Where("sex", 1).WhereIn("id").ToSql()
+	if sql == "SELECT * FROM `user` WHERE `sex` = ? AND 1 = 0" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").Where("sex", 1).OrWhereIn("id", []int{}).ToSql()
+	if sql == "SELECT * FROM `user` WHERE `sex` = ? OR 1 = 0" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").Where("sex", 1).WhereNotIn("id", []int{}).ToSql()
+	if sql == "SELECT * FROM `user` WHERE `sex` = ? AND 1 = 1" &&
+		reflect.DeepEqual(params, []interface{}{1}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_Where_Nil(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+		ptr    *string
+	)
+
+	sql, params = NewBuilder("user").Where("deleted_at", nil).Where("name", "!=", nil).Where("nick", ptr).ToSql()
+	if sql == "SELECT * FROM `user` WHERE `deleted_at` IS NULL AND `name` IS NOT NULL AND `nick` IS NULL" &&
+		reflect.DeepEqual(params, []interface{}{}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+
+	sql, params = NewBuilder("user").Where("name", "NULL").ToSql()
+	if sql == "SELECT * FROM `user` WHERE `name` = ?" &&
+		reflect.DeepEqual(params, []interface{}{"NULL"}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}
+
+func TestBuilder_WhereIn_Chunk(t *testing.T) {
+	var (
+		sql    string
+		params []interface{}
+	)
+
+	sql, params = NewBuilder("user").InChunkSize(2).Where("sex", 1).
+		WhereIn("id", []int{1, 2, 3}).WhereNotIn("pid", 4, 5, 6).ToSql()
+	if sql == "SELECT * FROM `user` WHERE `sex` = ? AND (`id` IN (?,?) OR `id` IN (?)) AND (`pid` NOT IN (?,?) AND `pid` NOT IN (?))" &&
+		reflect.DeepEqual(params, []interface{}{1, 1, 2, 3, 4, 5, 6}) {
+		t.Log(sql, params)
+	} else {
+		t.Error(sql, params)
+	}
+}